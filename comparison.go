@@ -0,0 +1,173 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ccoVeille/gh-reaction/internal/github"
+	"github.com/ccoVeille/gh-reaction/internal/timeago"
+)
+
+// comparePrevious is the value of -compare to compare with the preceding period of equal length.
+const comparePrevious = "previous"
+
+// comparePeriod is the period a report is compared to.
+type comparePeriod struct {
+	previous bool
+	period   timeago.DateRange
+}
+
+// String returns "previous" or the range to compare to.
+//
+// It satisfies the [flag.Value] and [fmt.Stringer] interface.
+func (c comparePeriod) String() string {
+	switch {
+	case c.previous:
+		return comparePrevious
+	case c.enabled():
+		return c.period.String()
+	default:
+		return ""
+	}
+}
+
+// Set sets the period to compare to, either "previous" or a range accepted by [timeago.DateRange.Set].
+//
+// It satisfies the [flag.Value] interface.
+func (c *comparePeriod) Set(value string) error {
+	if strings.EqualFold(value, comparePrevious) {
+		*c = comparePeriod{previous: true}
+		return nil
+	}
+
+	var period timeago.DateRange
+	if err := period.Set(value); err != nil {
+		return err
+	}
+	if period.Since.IsZero() || period.Until.IsZero() {
+		return errors.New(`the period to compare to must be "previous" or a range with both bounds (e.g., "Q1-2024")`)
+	}
+
+	*c = comparePeriod{period: period}
+	return nil
+}
+
+func (c comparePeriod) enabled() bool {
+	return c.previous || !c.period.Since.IsZero()
+}
+
+// resolve returns the period to compare to, the previous one is the period of equal length ending when period starts.
+func (c comparePeriod) resolve(period timeago.DateRange) timeago.DateRange {
+	if !c.previous {
+		return c.period
+	}
+
+	end := time.Now()
+	if !period.Until.IsZero() {
+		end = period.Until.Time
+	}
+
+	return timeago.DateRange{
+		Since: timeago.NewRelativeDate(period.Since.Add(-end.Sub(period.Since.Time))),
+		Until: period.Since,
+	}
+}
+
+// Comparison holds the statistics of the period a [Report] is compared to,
+// and the changes of the breakdowns between both periods.
+type Comparison struct {
+	Since     timeago.RelativeDate          `json:"since"`
+	Until     timeago.RelativeDate          `json:"until"`
+	Totals    ReportTotals                  `json:"totals"`
+	Reactions []ValueCountDiff[string]      `json:"reactions"`
+	Authors   []ValueCountDiff[github.User] `json:"authors"`
+	Users     []ValueCountDiff[github.User] `json:"users"`
+}
+
+func newComparison(current, previous Report) Comparison {
+	return Comparison{
+		Since:     previous.Since,
+		Until:     previous.Until,
+		Totals:    previous.Totals,
+		Reactions: current.Reactions.Diff(previous.Reactions, func(s string) string { return s }),
+		Authors:   current.Authors.Diff(previous.Authors, userLogin),
+		Users:     current.Users.Diff(previous.Users, userLogin),
+	}
+}
+
+func userLogin(u github.User) string {
+	return u.GetLogin()
+}
+
+// change returns the change of a total with the compared period, to be appended to the total.
+//
+// It returns an empty string when the report is not compared.
+func (r Report) change(total func(ReportTotals) int) string {
+	if r.Comparison == nil {
+		return ""
+	}
+	return fmt.Sprintf(" (%s)", formatChange(total(r.Totals), total(r.Comparison.Totals)))
+}
+
+// diffs returns a function formatting the changes of the values, or nil when the report is not compared.
+func diffs[T any](r Report, values func(Comparison) []ValueCountDiff[T], key func(T) string) func(T) string {
+	if r.Comparison == nil {
+		return nil
+	}
+
+	return func(value T) string {
+		diff, found := findDiff(values(*r.Comparison), key, value)
+		if !found {
+			return ""
+		}
+		return formatDiff(diff)
+	}
+}
+
+// findDiff returns the change of the value, matched by its key.
+func findDiff[T any](diffs []ValueCountDiff[T], key func(T) string, value T) (ValueCountDiff[T], bool) {
+	for _, diff := range diffs {
+		if key(diff.Value) == key(value) {
+			return diff, true
+		}
+	}
+	return ValueCountDiff[T]{}, false
+}
+
+// formatChange returns the change between the counts, such as "+3, +25%".
+func formatChange(count, previousCount int) string {
+	delta := count - previousCount
+	switch {
+	case delta == 0:
+		return "="
+	case previousCount == 0:
+		return fmt.Sprintf("%+d", delta)
+	default:
+		return fmt.Sprintf("%+d, %+.0f%%", delta, float64(delta)*100/float64(previousCount))
+	}
+}
+
+// formatRankMove returns the movement in a ranking, such as "↑2", "↓1", "=" or "new".
+func formatRankMove(rank, previousRank int) string {
+	switch {
+	case previousRank == 0:
+		return "new"
+	case rank == previousRank:
+		return "="
+	case rank < previousRank:
+		return fmt.Sprintf("↑%d", previousRank-rank)
+	default:
+		return fmt.Sprintf("↓%d", rank-previousRank)
+	}
+}
+
+// formatDiff returns the change of the count and the rank of a value.
+func formatDiff[T any](diff ValueCountDiff[T]) string {
+	change, move := formatChange(diff.Count, diff.PreviousCount), formatRankMove(diff.Rank, diff.PreviousRank)
+	if change == "=" && move == "=" {
+		return "="
+	}
+	return change + ", " + move
+}
@@ -0,0 +1,350 @@
+package main
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/ccoVeille/gh-reaction/internal/github"
+	"github.com/ccoVeille/gh-reaction/internal/timeago"
+)
+
+func TestValueCountsDiff(t *testing.T) {
+	identity := func(s string) string { return s }
+
+	tests := []struct {
+		name     string
+		current  ValueCounts[string]
+		previous ValueCounts[string]
+		expected []ValueCountDiff[string]
+	}{
+		{
+			name:     "Unchanged",
+			current:  ValueCounts[string]{{Value: "+1", Count: 3}},
+			previous: ValueCounts[string]{{Value: "+1", Count: 3}},
+			expected: []ValueCountDiff[string]{
+				{Value: "+1", Count: 3, PreviousCount: 3, Rank: 1, PreviousRank: 1},
+			},
+		},
+		{
+			name:     "Rank movement",
+			current:  ValueCounts[string]{{Value: "+1", Count: 2}, {Value: "heart", Count: 5}},
+			previous: ValueCounts[string]{{Value: "+1", Count: 4}, {Value: "heart", Count: 1}},
+			expected: []ValueCountDiff[string]{
+				{Value: "heart", Count: 5, PreviousCount: 1, Delta: 4, Rank: 1, PreviousRank: 2},
+				{Value: "+1", Count: 2, PreviousCount: 4, Delta: -2, Rank: 2, PreviousRank: 1},
+			},
+		},
+		{
+			name:     "New value",
+			current:  ValueCounts[string]{{Value: "+1", Count: 2}, {Value: "rocket", Count: 1}},
+			previous: ValueCounts[string]{{Value: "+1", Count: 2}},
+			expected: []ValueCountDiff[string]{
+				{Value: "+1", Count: 2, PreviousCount: 2, Rank: 1, PreviousRank: 1},
+				{Value: "rocket", Count: 1, Delta: 1, Rank: 2},
+			},
+		},
+		{
+			name:     "Value only in previous",
+			current:  ValueCounts[string]{{Value: "+1", Count: 2}},
+			previous: ValueCounts[string]{{Value: "eyes", Count: 3}, {Value: "+1", Count: 1}},
+			expected: []ValueCountDiff[string]{
+				{Value: "+1", Count: 2, PreviousCount: 1, Delta: 1, Rank: 1, PreviousRank: 2},
+				{Value: "eyes", PreviousCount: 3, Delta: -3, PreviousRank: 1},
+			},
+		},
+		{
+			name:     "No previous values",
+			current:  ValueCounts[string]{{Value: "+1", Count: 2}},
+			expected: []ValueCountDiff[string]{{Value: "+1", Count: 2, Delta: 2, Rank: 1}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.current.Diff(tt.previous, identity)
+			if !slices.Equal(got, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestFormatChange(t *testing.T) {
+	tests := []struct {
+		name          string
+		count         int
+		previousCount int
+		expected      string
+	}{
+		{"Unchanged", 4, 4, "="},
+		{"Increase", 15, 12, "+3, +25%"},
+		{"Decrease", 5, 6, "-1, -17%"},
+		{"Disappeared", 0, 3, "-3, -100%"},
+		{"Zero previous count", 3, 0, "+3"},
+		{"Both zero", 0, 0, "="},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := formatChange(tt.count, tt.previousCount)
+			if got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestFormatRankMove(t *testing.T) {
+	tests := []struct {
+		name         string
+		rank         int
+		previousRank int
+		expected     string
+	}{
+		{"Same rank", 2, 2, "="},
+		{"Up", 1, 3, "↑2"},
+		{"Down", 3, 2, "↓1"},
+		{"New", 4, 0, "new"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := formatRankMove(tt.rank, tt.previousRank)
+			if got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestFormatDiff(t *testing.T) {
+	tests := []struct {
+		name     string
+		diff     ValueCountDiff[string]
+		expected string
+	}{
+		{"Unchanged", ValueCountDiff[string]{Count: 2, PreviousCount: 2, Rank: 1, PreviousRank: 1}, "="},
+		{"Same count, other rank", ValueCountDiff[string]{Count: 2, PreviousCount: 2, Rank: 1, PreviousRank: 2}, "=, ↑1"},
+		{"New", ValueCountDiff[string]{Count: 2, Delta: 2, Rank: 3}, "+2, new"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := formatDiff(tt.diff)
+			if got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestComparePeriodSet(t *testing.T) {
+	tests := []struct {
+		name          string
+		value         string
+		expected      string
+		expectedError bool
+	}{
+		{"Previous", "previous", "previous", false},
+		{"Previous, case insensitive", "Previous", "previous", false},
+		{"Quarter", "Q1-2023", "2023-01-01T00:00:00Z..2023-04-01T00:00:00Z", false},
+		{"Range", "2023-01-01..2023-01-31", "2023-01-01T00:00:00Z..2023-02-01T00:00:00Z", false},
+		{"Open range", "2023-01-01..", "", true},
+		{"Single date", "2023-01-01", "", true},
+		{"Invalid", "yesterday-ish", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var c comparePeriod
+			err := c.Set(tt.value)
+			if tt.expectedError {
+				if err == nil {
+					t.Errorf("expected an error, got %v", c)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !c.enabled() {
+				t.Errorf("expected the comparison to be enabled")
+			}
+			if got := c.String(); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestComparePeriodResolve(t *testing.T) {
+	date := func(year int, month time.Month, day int) timeago.RelativeDate {
+		return timeago.NewRelativeDate(time.Date(year, month, day, 0, 0, 0, 0, time.UTC))
+	}
+
+	tests := []struct {
+		name     string
+		value    string
+		period   timeago.DateRange
+		expected timeago.DateRange
+	}{
+		{
+			name:     "Previous",
+			value:    "previous",
+			period:   timeago.DateRange{Since: date(2024, 3, 1), Until: date(2024, 3, 31)},
+			expected: timeago.DateRange{Since: date(2024, 1, 31), Until: date(2024, 3, 1)},
+		},
+		{
+			name:     "Range",
+			value:    "2023-03-01..2023-03-30",
+			period:   timeago.DateRange{Since: date(2024, 3, 1), Until: date(2024, 3, 31)},
+			expected: timeago.DateRange{Since: date(2023, 3, 1), Until: date(2023, 3, 31)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var c comparePeriod
+			if err := c.Set(tt.value); err != nil {
+				t.Fatal(err)
+			}
+
+			got := c.resolve(tt.period)
+			if !got.Since.Equal(tt.expected.Since.Time) || !got.Until.Equal(tt.expected.Until.Time) {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+
+	t.Run("Previous without until", func(t *testing.T) {
+		since := time.Now().Add(-72 * time.Hour)
+		period := timeago.DateRange{Since: timeago.NewRelativeDate(since)}
+
+		before := time.Now()
+		got := comparePeriod{previous: true}.resolve(period)
+		after := time.Now()
+
+		if !got.Until.Equal(since) {
+			t.Errorf("expected the compared period to end at %v, got %v", since, got.Until)
+		}
+		// the period ends now, the compared one is as long
+		length := since.Sub(got.Since.Time)
+		if length < before.Sub(since) || length > after.Sub(since) {
+			t.Errorf("expected a length between %v and %v, got %v", before.Sub(since), after.Sub(since), length)
+		}
+	})
+}
+
+func TestAnalyzeComparedPeriod(t *testing.T) {
+	date := func(month time.Month, day int) github.Time {
+		return github.Time{Time: time.Date(2024, month, day, 0, 0, 0, 0, time.UTC)}
+	}
+
+	opts := cliOptions{
+		dateField: dateFieldUpdated,
+		period:    timeago.DateRange{Since: timeago.NewRelativeDate(date(3, 1).Time), Until: timeago.NewRelativeDate(date(4, 1).Time)},
+		compare:   comparePeriod{previous: true},
+	}
+	opts.postsSince = opts.period.Since
+	comparedPeriod := opts.compare.resolve(opts.period)
+
+	// sorted by update date, as done by execute
+	posts := []Post{
+		{Link: "created in the period", CreatedAt: date(3, 10), UpdatedAt: date(3, 20)},
+		{Link: "created in the compared period", CreatedAt: date(2, 5), UpdatedAt: date(3, 15)},
+		{Link: "updated in the compared period", CreatedAt: date(2, 10), UpdatedAt: date(2, 20)},
+		{Link: "oldest", CreatedAt: date(2, 1), UpdatedAt: date(2, 15)},
+	}
+	for i := range posts {
+		posts[i].Date = opts.dateField.date(posts[i])
+	}
+
+	var reactions Reactions
+	for _, r := range []struct {
+		post      int
+		content   string
+		reactedAt github.Time
+	}{
+		{0, "+1", date(3, 12)},
+		{1, "heart", date(2, 22)},
+		{1, "+1", date(3, 16)},
+		{2, "rocket", date(2, 25)},
+		{3, "eyes", date(2, 16)},
+	} {
+		var reaction ReactionTo
+		reaction.Post = posts[r.post]
+		reaction.Reaction.Content = r.content
+		reaction.Reaction.CreatedAt = r.reactedAt
+		reactions = append(reactions, reaction)
+	}
+
+	tests := []struct {
+		name                      string
+		limit                     int
+		expectedComparedSince     time.Time
+		expectedReactions         []string
+		expectedComparedAnalyzed  int
+		expectedComparedReactions []string
+	}{
+		{
+			name:                      "No limit",
+			expectedComparedSince:     comparedPeriod.Since.Time,
+			expectedReactions:         []string{"+1", "+1"},
+			expectedComparedAnalyzed:  3,
+			expectedComparedReactions: []string{"eyes", "heart", "rocket"},
+		},
+		{
+			// the limit is reached in the compared period only
+			name:                      "Limit per period",
+			limit:                     2,
+			expectedComparedSince:     date(2, 20).Time,
+			expectedReactions:         []string{"+1", "+1"},
+			expectedComparedAnalyzed:  2,
+			expectedComparedReactions: []string{"heart", "rocket"},
+		},
+	}
+
+	contents := func(r Reactions) []string {
+		var c []string
+		for _, reaction := range r {
+			c = append(c, reaction.Reaction.Content)
+		}
+		slices.Sort(c)
+		return c
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := opts
+			opts.limit = tt.limit
+
+			current := selectPeriodPosts(posts, opts.dateField, opts.limit, opts.period, opts.postsSince)
+			compared := selectPeriodPosts(posts, opts.dateField, opts.limit, comparedPeriod, comparedPostsSince(opts, comparedPeriod))
+
+			report := analyze(opts, nil, current, reactions)
+			if !report.Since.Equal(opts.period.Since.Time) || !report.Until.Equal(opts.period.Until.Time) {
+				t.Errorf("expected the period %v, got %v..%v", opts.period, report.Since, report.Until)
+			}
+			if got := contents(report.Entries); !slices.Equal(got, tt.expectedReactions) {
+				t.Errorf("expected the reactions %v, got %v", tt.expectedReactions, got)
+			}
+
+			previous := analyze(opts, nil, compared, reactions)
+			if !previous.Since.Equal(tt.expectedComparedSince) || !previous.Until.Equal(comparedPeriod.Until.Time) {
+				t.Errorf("expected the compared period %v..%v, got %v..%v", tt.expectedComparedSince, comparedPeriod.Until, previous.Since, previous.Until)
+			}
+			if previous.Totals.AnalyzedPosts != tt.expectedComparedAnalyzed {
+				t.Errorf("expected %d analyzed posts in the compared period, got %d", tt.expectedComparedAnalyzed, previous.Totals.AnalyzedPosts)
+			}
+			if got := contents(previous.Entries); !slices.Equal(got, tt.expectedComparedReactions) {
+				t.Errorf("expected the compared reactions %v, got %v", tt.expectedComparedReactions, got)
+			}
+
+			comparison := newComparison(report, previous)
+			if comparison.Totals.Reactions != len(tt.expectedComparedReactions) {
+				t.Errorf("expected %d compared reactions, got %d", len(tt.expectedComparedReactions), comparison.Totals.Reactions)
+			}
+		})
+	}
+}
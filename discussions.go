@@ -0,0 +1,266 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/ccoVeille/gh-reaction/internal/gh"
+	"github.com/ccoVeille/gh-reaction/internal/github"
+	"github.com/ccoVeille/gh-reaction/internal/timeago"
+)
+
+// The discussions have an extra level of nesting with the replies, so the page sizes are smaller
+// than the ones of the issues to stay below the limit of 500,000 nodes per GraphQL query:
+// 10 discussions × 20 comments × 10 replies × 100 reactions.
+const (
+	graphqlDiscussionsPerPage        = 10
+	graphqlDiscussionCommentsPerPage = 20
+	graphqlDiscussionRepliesPerPage  = 10
+)
+
+var graphqlDiscussionReplyFields = fmt.Sprintf(`id databaseId body url createdAt updatedAt
+author { %s }
+%s`, graphqlActorFields, graphqlReactionsFields)
+
+var graphqlDiscussionRepliesFields = fmt.Sprintf(`pageInfo { hasNextPage endCursor }
+nodes { %s }`, graphqlDiscussionReplyFields)
+
+var graphqlDiscussionCommentsFields = fmt.Sprintf(`pageInfo { hasPreviousPage startCursor }
+nodes {
+	%s
+	replies(first: %d) { %s }
+}`, graphqlDiscussionReplyFields, graphqlDiscussionRepliesPerPage, graphqlDiscussionRepliesFields)
+
+var graphqlDiscussionsQuery = fmt.Sprintf(`query($owner: String!, $name: String!, $cursor: String) {
+	repository(owner: $owner, name: $name) {
+		discussions(first: %d, after: $cursor, orderBy: {field: UPDATED_AT, direction: DESC}) {
+			pageInfo { hasNextPage endCursor }
+			nodes {
+				id number title url createdAt updatedAt
+				author { %s }
+				%s
+				comments(last: %d) { %s }
+			}
+		}
+	}
+}`, graphqlDiscussionsPerPage, graphqlActorFields, graphqlReactionsFields, graphqlDiscussionCommentsPerPage, graphqlDiscussionCommentsFields)
+
+var graphqlDiscussionCommentsQuery = fmt.Sprintf(`query($id: ID!, $cursor: String) {
+	node(id: $id) {
+		... on Discussion { comments(last: %d, before: $cursor) { %s } }
+	}
+}`, graphqlDiscussionCommentsPerPage, graphqlDiscussionCommentsFields)
+
+var graphqlDiscussionRepliesQuery = fmt.Sprintf(`query($id: ID!, $cursor: String) {
+	node(id: $id) {
+		... on DiscussionComment { replies(first: %d, after: $cursor) { %s } }
+	}
+}`, graphqlDiscussionRepliesPerPage, graphqlDiscussionRepliesFields)
+
+type graphqlDiscussionComment struct {
+	ID         string                    `json:"id"`
+	DatabaseID int                       `json:"databaseId"`
+	Body       string                    `json:"body"`
+	URL        string                    `json:"url"`
+	CreatedAt  github.Time               `json:"createdAt"`
+	UpdatedAt  github.Time               `json:"updatedAt"`
+	Author     *github.Actor             `json:"author"`
+	Reactions  github.ReactionConnection `json:"reactions"`
+	Replies    graphqlDiscussionComments `json:"replies"`
+}
+
+type graphqlDiscussionComments struct {
+	PageInfo graphqlPageInfo            `json:"pageInfo"`
+	Nodes    []graphqlDiscussionComment `json:"nodes"`
+}
+
+type graphqlDiscussion struct {
+	ID        string                    `json:"id"`
+	Number    int                       `json:"number"`
+	Title     string                    `json:"title"`
+	URL       string                    `json:"url"`
+	CreatedAt github.Time               `json:"createdAt"`
+	UpdatedAt github.Time               `json:"updatedAt"`
+	Author    *github.Actor             `json:"author"`
+	Reactions github.ReactionConnection `json:"reactions"`
+	Comments  graphqlDiscussionComments `json:"comments"`
+}
+
+// fetchDiscussions fetches the discussions, their comments and the replies to the comments updated since minDate,
+// along with all their reactions.
+//
+// There is no REST API to list the reactions of the discussions, so they are all retrieved with the GraphQL API.
+func fetchDiscussions(ctx context.Context, client *gh.GraphQLClient, gitHubRepo gh.Repository, minDate timeago.RelativeDate, author string, addPost func(Post)) error {
+	f := discussionsFetcher{
+		client:  client,
+		repo:    gitHubRepo,
+		minDate: minDate,
+		author:  author,
+		addPost: addPost,
+	}
+
+	variables := map[string]any{
+		"owner": gitHubRepo.Owner,
+		"name":  gitHubRepo.Name,
+	}
+
+	for {
+		var response struct {
+			Repository struct {
+				Discussions struct {
+					PageInfo graphqlPageInfo     `json:"pageInfo"`
+					Nodes    []graphqlDiscussion `json:"nodes"`
+				} `json:"discussions"`
+			} `json:"repository"`
+		}
+		if err := client.Do(ctx, graphqlDiscussionsQuery, variables, &response); err != nil {
+			return err
+		}
+
+		discussions := response.Repository.Discussions
+		for _, discussion := range discussions.Nodes {
+			if discussion.UpdatedAt.Before(minDate.Time) {
+				// the discussions are sorted by update date, the next ones are older
+				return nil
+			}
+
+			if err := f.addDiscussion(ctx, discussion); err != nil {
+				return err
+			}
+		}
+
+		if !discussions.PageInfo.HasNextPage {
+			return nil
+		}
+		variables["cursor"] = discussions.PageInfo.EndCursor
+	}
+}
+
+// discussionsFetcher holds the settings shared while walking through the discussions.
+type discussionsFetcher struct {
+	client  *gh.GraphQLClient
+	repo    gh.Repository
+	minDate timeago.RelativeDate
+	author  string
+	addPost func(Post)
+}
+
+// addDiscussion adds a discussion and its comments, the comments are checked even if the discussion is not one of the author.
+func (f discussionsFetcher) addDiscussion(ctx context.Context, discussion graphqlDiscussion) error {
+	if author := discussion.Author.User(); authoredBy(author, f.author) {
+		reactions, err := fetchAllReactionsGraphQL(ctx, f.client, discussion.ID, discussion.Reactions)
+		if err != nil {
+			return err
+		}
+
+		f.addPost(Post{
+			Type:       PostTypeDiscussion,
+			Repository: f.repo,
+			CreatedAt:  discussion.CreatedAt,
+			UpdatedAt:  discussion.UpdatedAt,
+			Content:    discussion.Title,
+			Author:     author,
+			Link:       discussion.URL,
+			ID:         strconv.Itoa(discussion.Number),
+		}.withAllReactions(reactions))
+	}
+
+	comments := discussion.Comments
+	for {
+		for _, comment := range comments.Nodes {
+			if err := f.addComment(ctx, comment); err != nil {
+				return err
+			}
+		}
+
+		if !comments.PageInfo.HasPreviousPage {
+			return nil
+		}
+
+		var response struct {
+			Node struct {
+				Comments graphqlDiscussionComments `json:"comments"`
+			} `json:"node"`
+		}
+		variables := map[string]any{
+			"id":     discussion.ID,
+			"cursor": comments.PageInfo.StartCursor,
+		}
+		if err := f.client.Do(ctx, graphqlDiscussionCommentsQuery, variables, &response); err != nil {
+			return err
+		}
+		comments = response.Node.Comments
+	}
+}
+
+// addComment adds a comment and its replies, the replies are checked even if the comment is older than minDate.
+func (f discussionsFetcher) addComment(ctx context.Context, comment graphqlDiscussionComment) error {
+	if err := f.addReply(ctx, comment); err != nil {
+		return err
+	}
+
+	replies := comment.Replies
+	for {
+		for _, reply := range replies.Nodes {
+			if err := f.addReply(ctx, reply); err != nil {
+				return err
+			}
+		}
+
+		if !replies.PageInfo.HasNextPage {
+			return nil
+		}
+
+		var response struct {
+			Node struct {
+				Replies graphqlDiscussionComments `json:"replies"`
+			} `json:"node"`
+		}
+		variables := map[string]any{
+			"id":     comment.ID,
+			"cursor": replies.PageInfo.EndCursor,
+		}
+		if err := f.client.Do(ctx, graphqlDiscussionRepliesQuery, variables, &response); err != nil {
+			return err
+		}
+		replies = response.Node.Replies
+	}
+}
+
+// addReply adds a single discussion comment, without its replies.
+func (f discussionsFetcher) addReply(ctx context.Context, comment graphqlDiscussionComment) error {
+	author := comment.Author.User()
+	if comment.UpdatedAt.Before(f.minDate.Time) || !authoredBy(author, f.author) {
+		return nil
+	}
+
+	reactions, err := fetchAllReactionsGraphQL(ctx, f.client, comment.ID, comment.Reactions)
+	if err != nil {
+		return err
+	}
+
+	f.addPost(Post{
+		Type:       PostTypeDiscussionComment,
+		Repository: f.repo,
+		CreatedAt:  comment.CreatedAt,
+		UpdatedAt:  comment.UpdatedAt,
+		Content:    comment.Body,
+		Author:     author,
+		Link:       comment.URL,
+		ID:         strconv.Itoa(comment.DatabaseID),
+	}.withAllReactions(reactions))
+
+	return nil
+}
+
+// withAllReactions attaches the reactions fetched with [fetchAllReactionsGraphQL].
+//
+// Unlike [Post.withReactions], the reactions are attached even when they are fewer than their total count,
+// as reported for the reactions of deleted users: all the pages were walked, and there is no REST API
+// to fetch the reactions of the discussions.
+func (p Post) withAllReactions(reactions github.ReactionConnection) Post {
+	p.reactions = reactions.Reactions()
+	p.reactionsFetched = true
+	return p
+}
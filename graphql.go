@@ -0,0 +1,331 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/ccoVeille/gh-reaction/internal/gh"
+	"github.com/ccoVeille/gh-reaction/internal/github"
+	"github.com/ccoVeille/gh-reaction/internal/timeago"
+)
+
+// The page sizes are chosen to stay below the limit of 500,000 nodes per GraphQL query:
+// 25 posts × 50 comments × 100 reactions.
+const (
+	graphqlPostsPerPage     = 25
+	graphqlCommentsPerPage  = 50
+	graphqlReactionsPerPage = 100
+)
+
+const graphqlActorFields = `__typename login ... on User { name }`
+
+var graphqlReactionsConnection = fmt.Sprintf(`totalCount
+pageInfo { hasNextPage endCursor }
+nodes { content createdAt user { %s } }`, graphqlActorFields)
+
+var graphqlReactionsFields = fmt.Sprintf(`reactions(first: %d) { %s }`, graphqlReactionsPerPage, graphqlReactionsConnection)
+
+var graphqlCommentsFields = fmt.Sprintf(`pageInfo { hasPreviousPage startCursor }
+nodes {
+	databaseId body url createdAt updatedAt
+	author { %s }
+	%s
+}`, graphqlActorFields, graphqlReactionsFields)
+
+var graphqlPostFields = fmt.Sprintf(`id number title createdAt updatedAt
+author { %s }
+%s
+comments(last: %d) { %s }`, graphqlActorFields, graphqlReactionsFields, graphqlCommentsPerPage, graphqlCommentsFields)
+
+// The issues are not filtered by author, the comments of the author on the issues of other users are walked too.
+var graphqlIssuesQuery = fmt.Sprintf(`query($owner: String!, $name: String!, $since: DateTime, $cursor: String) {
+	repository(owner: $owner, name: $name) {
+		issues(first: %d, after: $cursor, filterBy: {since: $since}, orderBy: {field: UPDATED_AT, direction: DESC}) {
+			pageInfo { hasNextPage endCursor }
+			nodes { %s }
+		}
+	}
+}`, graphqlPostsPerPage, graphqlPostFields)
+
+// The pull requests cannot be filtered by date nor author, they are sorted by update date instead.
+var graphqlPullRequestsQuery = fmt.Sprintf(`query($owner: String!, $name: String!, $cursor: String) {
+	repository(owner: $owner, name: $name) {
+		pullRequests(first: %d, after: $cursor, orderBy: {field: UPDATED_AT, direction: DESC}) {
+			pageInfo { hasNextPage endCursor }
+			nodes { %s }
+		}
+	}
+}`, graphqlPostsPerPage, graphqlPostFields)
+
+var graphqlCommentsQuery = fmt.Sprintf(`query($id: ID!, $cursor: String) {
+	node(id: $id) {
+		... on Issue { comments(last: %[1]d, before: $cursor) { %[2]s } }
+		... on PullRequest { comments(last: %[1]d, before: $cursor) { %[2]s } }
+	}
+}`, graphqlCommentsPerPage, graphqlCommentsFields)
+
+var graphqlReactionsQuery = fmt.Sprintf(`query($id: ID!, $cursor: String) {
+	node(id: $id) {
+		... on Reactable { reactions(first: %d, after: $cursor) { %s } }
+	}
+}`, graphqlReactionsPerPage, graphqlReactionsConnection)
+
+type graphqlPageInfo struct {
+	HasNextPage     bool   `json:"hasNextPage"`
+	EndCursor       string `json:"endCursor"`
+	HasPreviousPage bool   `json:"hasPreviousPage"`
+	StartCursor     string `json:"startCursor"`
+}
+
+type graphqlComment struct {
+	DatabaseID int                       `json:"databaseId"`
+	Body       string                    `json:"body"`
+	URL        string                    `json:"url"`
+	CreatedAt  github.Time               `json:"createdAt"`
+	UpdatedAt  github.Time               `json:"updatedAt"`
+	Author     *github.Actor             `json:"author"`
+	Reactions  github.ReactionConnection `json:"reactions"`
+}
+
+type graphqlComments struct {
+	PageInfo graphqlPageInfo  `json:"pageInfo"`
+	Nodes    []graphqlComment `json:"nodes"`
+}
+
+type graphqlPost struct {
+	ID        string                    `json:"id"`
+	Number    int                       `json:"number"`
+	Title     string                    `json:"title"`
+	CreatedAt github.Time               `json:"createdAt"`
+	UpdatedAt github.Time               `json:"updatedAt"`
+	Author    *github.Actor             `json:"author"`
+	Reactions github.ReactionConnection `json:"reactions"`
+	Comments  graphqlComments           `json:"comments"`
+}
+
+type graphqlPosts struct {
+	PageInfo graphqlPageInfo `json:"pageInfo"`
+	Nodes    []graphqlPost   `json:"nodes"`
+}
+
+// withReactions attaches the reactions fetched along with the post, when they are complete.
+//
+// [Post.FetchReactions] falls back to the REST API for the posts with too many reactions.
+func (p Post) withReactions(reactions github.ReactionConnection) Post {
+	if reactions.Complete() {
+		p.reactions = reactions.Reactions()
+		p.reactionsFetched = true
+	}
+	return p
+}
+
+// fetchPostsGraphQL fetches the same posts as [fetchPosts] with their reactions, using the GraphQL API.
+//
+// The comments are retrieved through the issues and pull requests updated since minDate,
+// the pages of comments are walked back until a comment not updated since minDate is found.
+// The review comments, commit comments and releases are not reachable this way, they are fetched with the REST API.
+//
+// When author is set, the posts are filtered as soon as they are received: the issues and pull requests
+// cannot be filtered by the API, as the comments of the author on the ones of other users would be missed.
+func fetchPostsGraphQL(ctx context.Context, client *gh.GraphQLClient, restClient *gh.RESTClient, gitHubRepo gh.Repository, minDate timeago.RelativeDate, author string) ([]Post, error) {
+	suffix := fmt.Sprintf("on github.com/%s/%s since %s", gitHubRepo.Owner, gitHubRepo.Name, minDate.String())
+	if author != "" {
+		suffix = fmt.Sprintf("of %s %s", author, suffix)
+	}
+
+	fmt.Fprintf(os.Stderr, "Looking for posts %s\n", suffix)
+
+	var posts []Post
+
+	ctx, spin := startSpinner(ctx, "fetching posts")
+
+	addPost := func(post graphqlPost, postType PostType) error {
+		if post.UpdatedAt.Before(minDate.Time) {
+			return nil
+		}
+
+		if postAuthor := post.Author.User(); authoredBy(postAuthor, author) {
+			posts = append(posts, Post{
+				Type:       postType,
+				Repository: gitHubRepo,
+				CreatedAt:  post.CreatedAt,
+				UpdatedAt:  post.UpdatedAt,
+				Content:    post.Title,
+				Author:     postAuthor,
+				Link:       fmt.Sprintf("https://github.com/%s/%s/issues/%d", gitHubRepo.Owner, gitHubRepo.Name, post.Number),
+				ID:         strconv.Itoa(post.Number),
+			}.withReactions(post.Reactions))
+		}
+
+		comments := post.Comments
+		for {
+			for _, comment := range comments.Nodes {
+				commentAuthor := comment.Author.User()
+				if comment.UpdatedAt.Before(minDate.Time) || !authoredBy(commentAuthor, author) {
+					continue
+				}
+
+				posts = append(posts, Post{
+					Type:       PostTypeComment,
+					Repository: gitHubRepo,
+					CreatedAt:  comment.CreatedAt,
+					UpdatedAt:  comment.UpdatedAt,
+					Content:    comment.Body,
+					Author:     commentAuthor,
+					Link:       comment.URL,
+					ID:         strconv.Itoa(comment.DatabaseID),
+				}.withReactions(comment.Reactions))
+			}
+			spin.Progress("fetched %d posts", len(posts))
+
+			// the comments are sorted by creation date, the previous pages hold the older ones:
+			// stop once the oldest comment of the page was not updated since minDate
+			if !comments.PageInfo.HasPreviousPage || (len(comments.Nodes) > 0 && comments.Nodes[0].UpdatedAt.Before(minDate.Time)) {
+				return nil
+			}
+
+			var err error
+			comments, err = fetchCommentsGraphQL(ctx, client, post.ID, comments.PageInfo.StartCursor)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	variables := map[string]any{
+		"owner": gitHubRepo.Owner,
+		"name":  gitHubRepo.Name,
+	}
+	if !minDate.IsZero() {
+		variables["since"] = minDate.Format(time.RFC3339)
+	}
+
+	// Fetch issues
+	for {
+		var response struct {
+			Repository struct {
+				Issues graphqlPosts `json:"issues"`
+			} `json:"repository"`
+		}
+		if err := client.Do(ctx, graphqlIssuesQuery, variables, &response); err != nil {
+			return nil, err
+		}
+
+		issues := response.Repository.Issues
+		for _, issue := range issues.Nodes {
+			if err := addPost(issue, PostTypeIssue); err != nil {
+				return nil, err
+			}
+		}
+
+		if !issues.PageInfo.HasNextPage {
+			break
+		}
+		variables["cursor"] = issues.PageInfo.EndCursor
+	}
+
+	// Fetch pull requests
+	delete(variables, "since")
+	delete(variables, "cursor")
+	for {
+		var response struct {
+			Repository struct {
+				PullRequests graphqlPosts `json:"pullRequests"`
+			} `json:"repository"`
+		}
+		if err := client.Do(ctx, graphqlPullRequestsQuery, variables, &response); err != nil {
+			return nil, err
+		}
+
+		pullRequests := response.Repository.PullRequests
+		for _, pullRequest := range pullRequests.Nodes {
+			if err := addPost(pullRequest, PostTypePullRequest); err != nil {
+				return nil, err
+			}
+		}
+
+		last := len(pullRequests.Nodes) - 1
+		if !pullRequests.PageInfo.HasNextPage || (last >= 0 && pullRequests.Nodes[last].UpdatedAt.Before(minDate.Time)) {
+			// the next pages are older than minDate
+			break
+		}
+		variables["cursor"] = pullRequests.PageInfo.EndCursor
+	}
+
+	appendPost := func(post Post) {
+		posts = append(posts, post)
+		spin.Progress("fetched %d posts", len(posts))
+	}
+
+	for _, postType := range []PostType{PostTypeReviewComment, PostTypeCommitComment} {
+		if err := fetchComments(ctx, restClient, gitHubRepo, minDate, author, postType, appendPost); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := fetchReleases(ctx, restClient, gitHubRepo, minDate, author, appendPost); err != nil {
+		return nil, err
+	}
+
+	if err := fetchDiscussions(ctx, client, gitHubRepo, minDate, author, appendPost); err != nil {
+		return nil, err
+	}
+
+	spin.Done("✔️ fetched %d posts", len(posts))
+
+	// Sort posts by update time in descending order
+	slices.SortFunc(posts, func(a1, a2 Post) int {
+		return a2.UpdatedAt.Compare(a1.UpdatedAt.Time)
+	})
+
+	return posts, nil
+}
+
+// fetchAllReactionsGraphQL completes the reactions of a post, when they don't fit in the first page.
+func fetchAllReactionsGraphQL(ctx context.Context, client *gh.GraphQLClient, id string, reactions github.ReactionConnection) (github.ReactionConnection, error) {
+	page := reactions
+	for page.PageInfo.HasNextPage {
+		var response struct {
+			Node struct {
+				Reactions github.ReactionConnection `json:"reactions"`
+			} `json:"node"`
+		}
+
+		variables := map[string]any{
+			"id":     id,
+			"cursor": page.PageInfo.EndCursor,
+		}
+		if err := client.Do(ctx, graphqlReactionsQuery, variables, &response); err != nil {
+			return reactions, err
+		}
+
+		page = response.Node.Reactions
+		reactions.Nodes = append(reactions.Nodes, page.Nodes...)
+	}
+
+	reactions.PageInfo = page.PageInfo
+	return reactions, nil
+}
+
+// fetchCommentsGraphQL fetches the comments of an issue or a pull request posted before the cursor.
+func fetchCommentsGraphQL(ctx context.Context, client *gh.GraphQLClient, id, cursor string) (graphqlComments, error) {
+	var response struct {
+		Node struct {
+			Comments graphqlComments `json:"comments"`
+		} `json:"node"`
+	}
+
+	variables := map[string]any{
+		"id":     id,
+		"cursor": cursor,
+	}
+	if err := client.Do(ctx, graphqlCommentsQuery, variables, &response); err != nil {
+		return graphqlComments{}, err
+	}
+
+	return response.Node.Comments, nil
+}
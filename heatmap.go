@@ -0,0 +1,60 @@
+package main
+
+import (
+	"time"
+
+	"github.com/ccoVeille/gh-reaction/internal/timeago"
+)
+
+// Heatmap holds the number of reactions given per weekday and hour.
+type Heatmap struct {
+	TimeZone string `json:"time_zone"`
+	// Days are the weekdays, starting on Monday.
+	Days []HeatmapDay `json:"days"`
+	Max  int          `json:"max"`
+}
+
+// HeatmapDay holds the reactions given during a weekday of a [Heatmap].
+type HeatmapDay struct {
+	Weekday string  `json:"weekday"`
+	Hours   [24]int `json:"hours"`
+	Total   int     `json:"total"`
+}
+
+// newHeatmap counts the reactions given in the period per weekday and hour, in the time zone.
+func newHeatmap(reactions Reactions, loc *time.Location, period timeago.DateRange) Heatmap {
+	heatmap := Heatmap{
+		TimeZone: timeZoneName(loc),
+		Days:     make([]HeatmapDay, 7),
+	}
+	for i := range heatmap.Days {
+		heatmap.Days[i].Weekday = time.Weekday((i + 1) % 7).String()
+	}
+
+	for _, reaction := range reactions {
+		createdAt := reaction.Reaction.CreatedAt.Time
+		if !period.Contains(createdAt) {
+			continue
+		}
+
+		createdAt = createdAt.In(loc)
+		day := &heatmap.Days[(int(createdAt.Weekday())+6)%7]
+		day.Hours[createdAt.Hour()]++
+		day.Total++
+		heatmap.Max = max(heatmap.Max, day.Hours[createdAt.Hour()])
+	}
+
+	return heatmap
+}
+
+// heatmapShades are the block characters of increasing density used to render a [Heatmap].
+var heatmapShades = []rune(" ░▒▓█")
+
+// shade returns the block character of the count, relative to the maximum of the heatmap.
+func (h Heatmap) shade(count int) rune {
+	if count <= 0 || h.Max <= 0 {
+		return heatmapShades[0]
+	}
+	// round up, so the smallest counts are visible
+	return heatmapShades[(count*(len(heatmapShades)-1)+h.Max-1)/h.Max]
+}
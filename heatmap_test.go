@@ -0,0 +1,77 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ccoVeille/gh-reaction/internal/timeago"
+)
+
+func TestNewHeatmap(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	period := timeago.DateRange{
+		Since: timeago.NewRelativeDate(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)),
+		Until: timeago.NewRelativeDate(time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)),
+	}
+
+	var reactions Reactions
+	for _, reactedAt := range []time.Time{
+		time.Date(2024, 3, 3, 10, 0, 0, 0, time.UTC),  // Sunday 19:00 in Tokyo
+		time.Date(2024, 3, 3, 10, 30, 0, 0, time.UTC), // Sunday 19:30 in Tokyo
+		time.Date(2024, 3, 3, 20, 0, 0, 0, time.UTC),  // Monday 05:00 in Tokyo
+		time.Date(2024, 2, 29, 10, 0, 0, 0, time.UTC), // before the period
+	} {
+		var r ReactionTo
+		r.Reaction.CreatedAt.Time = reactedAt
+		reactions = append(reactions, r)
+	}
+
+	heatmap := newHeatmap(reactions, tokyo, period)
+	if heatmap.TimeZone != "Asia/Tokyo" {
+		t.Errorf("expected the time zone Asia/Tokyo, got %q", heatmap.TimeZone)
+	}
+	if len(heatmap.Days) != 7 || heatmap.Days[0].Weekday != "Monday" || heatmap.Days[6].Weekday != "Sunday" {
+		t.Fatalf("expected the days from Monday to Sunday, got %+v", heatmap.Days)
+	}
+
+	sunday, monday := heatmap.Days[6], heatmap.Days[0]
+	if sunday.Hours[19] != 2 || sunday.Total != 2 {
+		t.Errorf("expected 2 reactions on Sunday at 19:00, got %d out of %d", sunday.Hours[19], sunday.Total)
+	}
+	if monday.Hours[5] != 1 || monday.Total != 1 {
+		t.Errorf("expected 1 reaction on Monday at 05:00, got %d out of %d", monday.Hours[5], monday.Total)
+	}
+	if heatmap.Max != 2 {
+		t.Errorf("expected a maximum of 2, got %d", heatmap.Max)
+	}
+}
+
+func TestHeatmapShade(t *testing.T) {
+	tests := []struct {
+		name     string
+		max      int
+		count    int
+		expected rune
+	}{
+		{"Zero", 10, 0, ' '},
+		{"Empty heatmap", 0, 0, ' '},
+		{"Smallest count is visible", 100, 1, '░'},
+		{"Quarter", 100, 25, '░'},
+		{"Above a quarter", 100, 26, '▒'},
+		{"Half", 100, 50, '▒'},
+		{"Three quarters", 100, 75, '▓'},
+		{"Maximum", 100, 100, '█'},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := (Heatmap{Max: tt.max}).shade(tt.count); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
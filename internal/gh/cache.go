@@ -0,0 +1,232 @@
+package gh
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// CacheMaxAge is the duration after which an unused entry is removed by [CacheTransport.Prune].
+const CacheMaxAge = 30 * 24 * time.Hour
+
+// CacheDir returns the directory where the responses of the GitHub API are cached.
+func CacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "gh-reaction"), nil
+}
+
+// CacheTransport is a [http.RoundTripper] that stores the responses of the GitHub API on disk
+// along with their ETag.
+//
+// The cached responses are revalidated with an If-None-Match header. The GitHub API replies
+// "304 Not Modified" when the resource didn't change, and such responses don't count against the rate limit.
+//
+// Only the GET requests are cached, the entries are keyed by host, token, repository and endpoint:
+// the accounts of a machine don't share their responses.
+type CacheTransport struct {
+	// Dir is the directory where the responses are stored.
+	Dir string
+
+	// Base is the transport used to send the requests, [http.DefaultTransport] is used when nil.
+	Base http.RoundTripper
+}
+
+// NewCacheTransport creates a new [CacheTransport] storing the responses in dir.
+func NewCacheTransport(dir string, base http.RoundTripper) *CacheTransport {
+	return &CacheTransport{
+		Dir:  dir,
+		Base: base,
+	}
+}
+
+// cacheEntry is the representation of a response stored on disk.
+type cacheEntry struct {
+	ETag   string      `json:"etag"`
+	Header http.Header `json:"header"`
+	Body   []byte      `json:"body"`
+}
+
+// RoundTrip sends the request, using the cached response when it's still valid.
+//
+// It satisfies the [http.RoundTripper] interface.
+func (t *CacheTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	if req.Method != http.MethodGet || req.Header.Get("If-None-Match") != "" {
+		return base.RoundTrip(req)
+	}
+
+	path := t.entryPath(req)
+	entry, found := readCacheEntry(path)
+	if found {
+		req = req.Clone(req.Context())
+		req.Header.Set("If-None-Match", entry.ETag)
+	}
+
+	resp, err := base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if found && resp.StatusCode == http.StatusNotModified {
+		resp.Body.Close()
+
+		// keep the entry from being pruned
+		now := time.Now()
+		_ = os.Chtimes(path, now, now)
+
+		return entry.response(req), nil
+	}
+
+	etag := resp.Header.Get("ETag")
+	if resp.StatusCode != http.StatusOK || etag == "" {
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	// the cache is a best effort, a failure to write it must not fail the request
+	_ = writeCacheEntry(path, cacheEntry{
+		ETag:   etag,
+		Header: resp.Header,
+		Body:   body,
+	})
+
+	return resp, nil
+}
+
+// Prune removes the entries not used for maxAge, they are not revalidated anymore.
+//
+// The entries are used when they are revalidated, a missing directory is not an error.
+func (t *CacheTransport) Prune(maxAge time.Duration) error {
+	limit := time.Now().Add(-maxAge)
+
+	err := filepath.WalkDir(t.Dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || filepath.Ext(path) != ".json" {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if info.ModTime().Before(limit) {
+			return os.Remove(path)
+		}
+		return nil
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// entryPath returns the path of the file caching the response of the request.
+//
+// The files are stored in a directory per host, token and repository, and named after a hash of the endpoint.
+// The token is hashed too, so it's not written on disk.
+//
+// The query is part of the key, including the since parameter of the list endpoints that changes
+// every hour: the Link header of a cached page points to the next pages of the same query.
+// The entries of the previous queries are removed by [CacheTransport.Prune].
+func (t *CacheTransport) entryPath(req *http.Request) string {
+	u := req.URL
+
+	identity := "anonymous"
+	if auth := req.Header.Get("Authorization"); auth != "" {
+		hash := sha256.Sum256([]byte(auth))
+		identity = hex.EncodeToString(hash[:8])
+	}
+
+	// GitHub Enterprise Server API is served under /api/v3
+	endpoint := strings.TrimPrefix(u.Path, "/api/v3")
+
+	repo := "_"
+	if rest, found := strings.CutPrefix(endpoint, "/repos/"); found {
+		parts := strings.SplitN(rest, "/", 3)
+		if len(parts) >= 2 {
+			repo = filepath.Join(parts[0], parts[1])
+		}
+	}
+
+	hash := sha256.Sum256([]byte(endpoint + "?" + u.RawQuery))
+	return filepath.Join(t.Dir, u.Host, identity, repo, hex.EncodeToString(hash[:])+".json")
+}
+
+func (e cacheEntry) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        e.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       req,
+	}
+}
+
+func readCacheEntry(path string) (cacheEntry, bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cacheEntry{}, false
+	}
+
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil || entry.ETag == "" {
+		// a corrupted entry is ignored, it will be overwritten
+		return cacheEntry{}, false
+	}
+
+	return entry, true
+}
+
+func writeCacheEntry(path string, entry cacheEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+
+	// write to a temporary file first, so concurrent readers never see a partial entry
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".entry-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(data)
+	err = errors.Join(err, tmp.Close())
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
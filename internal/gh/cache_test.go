@@ -0,0 +1,173 @@
+package gh
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCacheTransport(t *testing.T) {
+	const etag = `"abc"`
+
+	var requests, notModified int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-None-Match") == etag {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Link", `<https://example.com?page=2>; rel="next"`)
+		fmt.Fprint(w, `[1, 2]`)
+	}))
+	defer srv.Close()
+
+	transport := NewCacheTransport(t.TempDir(), nil)
+
+	for i := range 3 {
+		req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, srv.URL+"/repos/owner/repo/issues?page=1", http.NoBody)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resp, err := transport.RoundTrip(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("request %d: expected status %d, got %d", i, http.StatusOK, resp.StatusCode)
+		}
+		if string(body) != `[1, 2]` {
+			t.Errorf("request %d: unexpected body %q", i, body)
+		}
+		if resp.Header.Get("Link") == "" {
+			t.Errorf("request %d: missing Link header", i)
+		}
+	}
+
+	if requests != 3 || notModified != 2 {
+		t.Errorf("expected 3 requests including 2 revalidated, got %d requests including %d revalidated", requests, notModified)
+	}
+}
+
+func TestCacheTransportEntryPath(t *testing.T) {
+	transport := NewCacheTransport("cache", nil)
+
+	tests := []struct {
+		url      string
+		expected string
+	}{
+		{
+			url:      "https://api.github.com/repos/owner/repo/issues?page=2",
+			expected: filepath.Join("cache", "api.github.com", "anonymous", "owner", "repo"),
+		},
+		{
+			url:      "https://github.example.com/api/v3/repos/owner/repo/issues/comments",
+			expected: filepath.Join("cache", "github.example.com", "anonymous", "owner", "repo"),
+		},
+		{
+			url:      "https://api.github.com/search/issues?q=author:me",
+			expected: filepath.Join("cache", "api.github.com", "anonymous", "_"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, tt.url, http.NoBody)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := filepath.Dir(transport.entryPath(req))
+			if got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestCacheTransportEntryPathToken(t *testing.T) {
+	transport := NewCacheTransport("cache", nil)
+
+	entryPath := func(token string) string {
+		req, err := http.NewRequest(http.MethodGet, "https://api.github.com/repos/owner/private/issues", http.NoBody)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if token != "" {
+			req.Header.Set("Authorization", "token "+token)
+		}
+		return transport.entryPath(req)
+	}
+
+	first, second := entryPath("first"), entryPath("second")
+	if first == second {
+		t.Errorf("expected the accounts to have their own entries, got %q for both", first)
+	}
+	if first != entryPath("first") {
+		t.Error("expected the entry of an account to be stable")
+	}
+	if strings.Contains(first, "first") {
+		t.Errorf("expected the token to be hashed, got %q", first)
+	}
+	if first == entryPath("") {
+		t.Error("expected the anonymous requests to have their own entries")
+	}
+}
+
+func TestCacheTransportPrune(t *testing.T) {
+	dir := t.TempDir()
+	transport := NewCacheTransport(dir, nil)
+
+	now := time.Now()
+	entries := []struct {
+		name     string
+		age      time.Duration
+		expected bool
+	}{
+		{filepath.Join("api.github.com", "anonymous", "owner", "repo", "recent.json"), time.Hour, true},
+		{filepath.Join("api.github.com", "anonymous", "owner", "repo", "old.json"), 31 * 24 * time.Hour, false},
+		{filepath.Join("api.github.com", "anonymous", "_", "old.json"), 60 * 24 * time.Hour, false},
+		{filepath.Join("api.github.com", "notes.txt"), 60 * 24 * time.Hour, true},
+	}
+	for _, e := range entries {
+		path := filepath.Join(dir, e.name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(`{}`), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, now.Add(-e.age), now.Add(-e.age)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := transport.Prune(CacheMaxAge); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, e := range entries {
+		_, err := os.Stat(filepath.Join(dir, e.name))
+		if found := err == nil; found != e.expected {
+			t.Errorf("%s: expected found to be %v, got %v", e.name, e.expected, found)
+		}
+	}
+
+	if err := NewCacheTransport(filepath.Join(dir, "missing"), nil).Prune(CacheMaxAge); err != nil {
+		t.Errorf("expected no error for a missing directory, got %v", err)
+	}
+}
@@ -17,8 +17,12 @@ type RESTClient struct {
 type ClientOptions = api.ClientOptions
 
 // DefaultRESTClient creates a new [RESTClient] with default options.
+//
+// The requests wait and retry when the rate limits are hit, see [RateLimitTransport].
 func DefaultRESTClient() (*RESTClient, error) {
-	return NewRESTClient(ClientOptions{})
+	return NewRESTClient(ClientOptions{
+		Transport: NewRateLimitTransport(nil),
+	})
 }
 
 // NewRESTClient creates a new [RESTClient] with the given [ClientOptions].
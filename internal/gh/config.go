@@ -0,0 +1,15 @@
+package gh
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// ConfigDir returns the directory where the configuration files are read.
+func ConfigDir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "gh-reaction"), nil
+}
@@ -0,0 +1,75 @@
+package gh
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+// graphqlRateLimited is the type of the errors returned by the GraphQL API when a rate limit is hit.
+//
+// These errors come with a 200 status code, [RateLimitTransport] doesn't see them.
+const graphqlRateLimited = "RATE_LIMITED"
+
+// GraphQLClient is a wrapper around go-gh package [api.GraphQLClient] that adds context awareness to its methods.
+type GraphQLClient struct {
+	client *api.GraphQLClient
+
+	// maxRetries is the maximum number of times a rate limited query is retried.
+	maxRetries int
+
+	// rateLimitWait is the wait before retrying a rate limited query, it doubles on each retry.
+	rateLimitWait time.Duration
+}
+
+// NewGraphQLClient creates a new [GraphQLClient] with the given [ClientOptions].
+func NewGraphQLClient(opts ClientOptions) (*GraphQLClient, error) {
+	client, err := api.NewGraphQLClient(opts)
+	if err != nil {
+		return nil, err
+	}
+	return &GraphQLClient{
+		client:        client,
+		maxRetries:    5,
+		rateLimitWait: time.Minute,
+	}, nil
+}
+
+// Do executes a GraphQL query and decodes its data into response, it mimics [api.GraphQLClient.Do], but
+// adds context awareness.
+//
+// The queries failing with a RATE_LIMITED error are retried after waiting one minute, then twice as long
+// on each retry, as the GraphQL API doesn't tell how long to wait. The waits are reported like the ones of
+// [RateLimitTransport], and interrupted when the context is canceled.
+func (c *GraphQLClient) Do(ctx context.Context, query string, variables map[string]any, response any) error {
+	wait := c.rateLimitWait
+	for attempt := 0; ; attempt++ {
+		err := c.client.DoWithContext(ctx, query, variables, response)
+		if attempt >= c.maxRetries || !isGraphQLRateLimited(err) {
+			return err
+		}
+
+		notifyWait(ctx, wait, "GraphQL rate limit exceeded")
+		if err := sleep(ctx, wait); err != nil {
+			return err
+		}
+		wait *= 2
+	}
+}
+
+// isGraphQLRateLimited reports whether the error is a GraphQL error caused by a rate limit.
+func isGraphQLRateLimited(err error) bool {
+	var gqlErr *api.GraphQLError
+	if !errors.As(err, &gqlErr) {
+		return false
+	}
+
+	for _, item := range gqlErr.Errors {
+		if item.Type == graphqlRateLimited {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,108 @@
+package gh
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// redirectTransport sends the requests to a test server, whatever their host.
+type redirectTransport struct {
+	target *url.URL
+}
+
+func (t redirectTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = t.target.Scheme
+	req.URL.Host = t.target.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestGraphQLClientRateLimited(t *testing.T) {
+	tests := []struct {
+		name          string
+		failures      int
+		errorType     string
+		expectedCalls int
+		expectedWaits int
+		wantErr       bool
+	}{
+		{
+			name:          "Retried",
+			failures:      2,
+			errorType:     "RATE_LIMITED",
+			expectedCalls: 3,
+			expectedWaits: 2,
+		},
+		{
+			name:          "Too many failures",
+			failures:      10,
+			errorType:     "RATE_LIMITED",
+			expectedCalls: 4,
+			expectedWaits: 3,
+			wantErr:       true,
+		},
+		{
+			name:          "Other error",
+			failures:      1,
+			errorType:     "NOT_FOUND",
+			expectedCalls: 1,
+			wantErr:       true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				calls++
+				if calls <= tt.failures {
+					// the GraphQL API reports the errors with a 200 status code
+					fmt.Fprintf(w, `{"data": null, "errors": [{"type": %q, "message": "failure"}]}`, tt.errorType)
+					return
+				}
+				fmt.Fprint(w, `{"data": {"viewer": {"login": "octocat"}}}`)
+			}))
+			defer srv.Close()
+
+			target, err := url.Parse(srv.URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			client, err := NewGraphQLClient(ClientOptions{Host: "github.com", AuthToken: "token", Transport: redirectTransport{target: target}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			client.maxRetries = 3
+			client.rateLimitWait = time.Millisecond
+
+			var waits int
+			ctx := WithWaitNotifier(t.Context(), func(time.Duration, string) {
+				waits++
+			})
+
+			var response struct {
+				Viewer struct {
+					Login string `json:"login"`
+				} `json:"viewer"`
+			}
+			err = client.Do(ctx, "query { viewer { login } }", nil, &response)
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !tt.wantErr && response.Viewer.Login != "octocat" {
+				t.Errorf("expected the response to be decoded, got %+v", response)
+			}
+			if calls != tt.expectedCalls {
+				t.Errorf("expected %d calls, got %d", tt.expectedCalls, calls)
+			}
+			if waits != tt.expectedWaits {
+				t.Errorf("expected %d waits, got %d", tt.expectedWaits, waits)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package gh
+
+import (
+	"context"
+	"encoding/json"
+	"iter"
+	"net/http"
+	"strings"
+)
+
+// Paginate calls the GitHub API to retrieve a paginated list of resources.
+//
+// It yields the resources page by page, following the "next" relation of the
+// Link header until the last page is reached, so no extra request is made for an empty page.
+// The iteration stops on the first error.
+func Paginate[T any](ctx context.Context, c *RESTClient, path string) iter.Seq2[[]T, error] {
+	return paginate[[]T](ctx, c, path)
+}
+
+// SearchResult is a page of results of the search API.
+type SearchResult[T any] struct {
+	TotalCount        int  `json:"total_count"`
+	IncompleteResults bool `json:"incomplete_results"`
+	Items             []T  `json:"items"`
+}
+
+// Search calls the GitHub search API, and yields the results page by page like [Paginate].
+//
+// The search API returns at most 1000 results, whatever the total count is.
+func Search[T any](ctx context.Context, c *RESTClient, path string) iter.Seq2[SearchResult[T], error] {
+	return paginate[SearchResult[T]](ctx, c, path)
+}
+
+// PaginateBackward calls the GitHub API to retrieve a paginated list of resources, from the last page to the first one.
+//
+// It's meant for the endpoints listing the oldest resources first, that cannot be sorted nor filtered by date:
+// the first page is requested to find the last one, then the "prev" relation of the Link header is followed.
+// The first page is yielded last, without requesting it again. The resources of each page are in the order of the API.
+// The iteration stops on the first error.
+func PaginateBackward[T any](ctx context.Context, c *RESTClient, path string) iter.Seq2[[]T, error] {
+	return func(yield func([]T, error) bool) {
+		firstPage, link, err := getPage[[]T](ctx, c, path)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+
+		for path := linkRelation(link, "last"); path != ""; {
+			var page []T
+			page, link, err = getPage[[]T](ctx, c, path)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+
+			if !yield(page, nil) {
+				return
+			}
+
+			path = linkRelation(link, "prev")
+			if path == linkRelation(link, "first") {
+				// the first page was already retrieved
+				break
+			}
+		}
+
+		yield(firstPage, nil)
+	}
+}
+
+func paginate[P any](ctx context.Context, c *RESTClient, path string) iter.Seq2[P, error] {
+	return func(yield func(P, error) bool) {
+		next := path
+		for next != "" {
+			page, link, err := getPage[P](ctx, c, next)
+			if err != nil {
+				yield(page, err)
+				return
+			}
+
+			if !yield(page, nil) {
+				return
+			}
+			next = nextPage(link)
+		}
+	}
+}
+
+// getPage retrieves a single page and returns its Link header.
+func getPage[P any](ctx context.Context, c *RESTClient, path string) (P, string, error) {
+	var page P
+
+	resp, err := c.Request(ctx, http.MethodGet, path, http.NoBody)
+	if err != nil {
+		return page, "", err
+	}
+	defer resp.Body.Close()
+
+	if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
+		return page, "", err
+	}
+
+	return page, resp.Header.Get("Link"), nil
+}
+
+// nextPage extracts the URL of the "next" relation from a Link header.
+//
+// The header looks like: <https://api.github.com/…&page=2>; rel="next", <https://api.github.com/…&page=5>; rel="last"
+func nextPage(link string) string {
+	return linkRelation(link, "next")
+}
+
+// linkRelation extracts the URL of a relation such as "next", "prev", "first" or "last" from a Link header.
+func linkRelation(link, rel string) string {
+	for _, part := range strings.Split(link, ",") {
+		target, params, found := strings.Cut(part, ";")
+		if !found {
+			continue
+		}
+
+		for _, param := range strings.Split(params, ";") {
+			if strings.TrimSpace(param) != `rel="`+rel+`"` {
+				continue
+			}
+
+			target = strings.TrimSpace(target)
+			return strings.TrimSuffix(strings.TrimPrefix(target, "<"), ">")
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,164 @@
+package gh
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestNextPage(t *testing.T) {
+	tests := []struct {
+		name     string
+		link     string
+		expected string
+	}{
+		{
+			name:     "Empty",
+			link:     "",
+			expected: "",
+		},
+		{
+			name:     "Next and last",
+			link:     `<https://api.github.com/repositories/1/issues?page=2>; rel="next", <https://api.github.com/repositories/1/issues?page=5>; rel="last"`,
+			expected: "https://api.github.com/repositories/1/issues?page=2",
+		},
+		{
+			name:     "Last page",
+			link:     `<https://api.github.com/repositories/1/issues?page=4>; rel="prev", <https://api.github.com/repositories/1/issues?page=1>; rel="first"`,
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := nextPage(tt.link)
+			if got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestPaginate(t *testing.T) {
+	const lastPage = 3
+
+	var requests int
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		if page < lastPage {
+			w.Header().Set("Link", fmt.Sprintf(`<%s/items?page=%d>; rel="next"`, srv.URL, page+1))
+		}
+		fmt.Fprintf(w, "[%d, %d]", page*10, page*10+1)
+	}))
+	defer srv.Close()
+
+	client, err := NewRESTClient(ClientOptions{Host: "github.com", AuthToken: "token"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []int
+	for page, err := range Paginate[int](t.Context(), client, srv.URL+"/items?page=1") {
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, page...)
+	}
+
+	expected := []int{10, 11, 20, 21, 30, 31}
+	if !slices.Equal(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+	if requests != lastPage {
+		t.Errorf("expected %d requests, got %d", lastPage, requests)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page") == "" {
+			w.Header().Set("Link", fmt.Sprintf(`<http://%s/search?page=2>; rel="next"`, r.Host))
+			fmt.Fprint(w, `{"total_count": 3, "incomplete_results": false, "items": [1, 2]}`)
+			return
+		}
+		fmt.Fprint(w, `{"total_count": 3, "incomplete_results": false, "items": [3]}`)
+	}))
+	defer srv.Close()
+
+	client, err := NewRESTClient(ClientOptions{Host: "github.com", AuthToken: "token"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []int
+	for page, err := range Search[int](t.Context(), client, srv.URL+"/search") {
+		if err != nil {
+			t.Fatal(err)
+		}
+		if page.TotalCount != 3 {
+			t.Errorf("expected a total count of 3, got %d", page.TotalCount)
+		}
+		got = append(got, page.Items...)
+	}
+
+	expected := []int{1, 2, 3}
+	if !slices.Equal(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestPaginateBackward(t *testing.T) {
+	const lastPage = 3
+
+	var requested []int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		requested = append(requested, page)
+
+		current := max(page, 1)
+		var links []string
+		if current > 1 {
+			links = append(links,
+				fmt.Sprintf(`<http://%s/items?page=%d>; rel="prev"`, r.Host, current-1),
+				fmt.Sprintf(`<http://%s/items?page=1>; rel="first"`, r.Host),
+			)
+		}
+		if current < lastPage {
+			links = append(links,
+				fmt.Sprintf(`<http://%s/items?page=%d>; rel="next"`, r.Host, current+1),
+				fmt.Sprintf(`<http://%s/items?page=%d>; rel="last"`, r.Host, lastPage),
+			)
+		}
+		w.Header().Set("Link", strings.Join(links, ", "))
+		fmt.Fprintf(w, "[%d, %d]", current*10, current*10+1)
+	}))
+	defer srv.Close()
+
+	client, err := NewRESTClient(ClientOptions{Host: "github.com", AuthToken: "token"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []int
+	for page, err := range PaginateBackward[int](t.Context(), client, srv.URL+"/items") {
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, page...)
+	}
+
+	expected := []int{30, 31, 20, 21, 10, 11}
+	if !slices.Equal(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+	// the first page is not requested twice
+	if expectedRequests := []int{0, 3, 2}; !slices.Equal(requested, expectedRequests) {
+		t.Errorf("expected the pages %v to be requested, got %v", expectedRequests, requested)
+	}
+}
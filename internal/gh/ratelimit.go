@@ -0,0 +1,173 @@
+package gh
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RateLimitTransport is a [http.RoundTripper] that waits when the GitHub API rate limits are hit,
+// and retries the requests failing with a transient error.
+//
+// It handles:
+//   - the primary rate limit, by waiting until the time given by the X-RateLimit-Reset header,
+//   - the secondary rate limits, by waiting for the delay given by the Retry-After header,
+//     or one minute when the header is missing,
+//   - the server errors, by retrying with an exponential backoff.
+//
+// The waits are interrupted when the context of the request is canceled.
+type RateLimitTransport struct {
+	// Base is the transport used to send the requests, [http.DefaultTransport] is used when nil.
+	Base http.RoundTripper
+
+	// MaxRetries is the maximum number of times a request is retried.
+	MaxRetries int
+
+	// Backoff is the wait before retrying a request failing with a server error.
+	// It doubles on each retry.
+	Backoff time.Duration
+}
+
+// NewRateLimitTransport creates a new [RateLimitTransport] with default settings.
+func NewRateLimitTransport(base http.RoundTripper) *RateLimitTransport {
+	return &RateLimitTransport{
+		Base:       base,
+		MaxRetries: 5,
+		Backoff:    time.Second,
+	}
+}
+
+// RoundTrip sends the request, and retries it when needed.
+//
+// It satisfies the [http.RoundTripper] interface.
+func (t *RateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	backoff := t.Backoff
+	for attempt := 0; ; attempt++ {
+		attemptReq := req
+		if attempt > 0 {
+			attemptReq = req.Clone(ctx)
+			if req.GetBody != nil {
+				body, err := req.GetBody()
+				if err != nil {
+					return nil, err
+				}
+				attemptReq.Body = body
+			}
+		}
+
+		resp, err := base.RoundTrip(attemptReq)
+		if err != nil {
+			return nil, err
+		}
+
+		canRetry := attempt < t.MaxRetries && (req.Body == nil || req.Body == http.NoBody || req.GetBody != nil)
+		if !canRetry {
+			return resp, nil
+		}
+
+		wait, reason := retryDelay(resp, backoff)
+		if reason == "" {
+			return resp, nil
+		}
+
+		// the response is discarded, the connection can be reused
+		_, _ = io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+
+		notifyWait(ctx, wait, reason)
+		if err := sleep(ctx, wait); err != nil {
+			return nil, err
+		}
+
+		if resp.StatusCode >= http.StatusInternalServerError {
+			backoff *= 2
+		}
+	}
+}
+
+// retryDelay returns how long to wait before retrying the request, and why.
+//
+// The reason is empty when the request must not be retried.
+func retryDelay(resp *http.Response, backoff time.Duration) (time.Duration, string) {
+	switch resp.StatusCode {
+	case http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return backoff, fmt.Sprintf("server error %d", resp.StatusCode)
+	case http.StatusForbidden, http.StatusTooManyRequests:
+		// rate limits are reported with these status codes
+	default:
+		return 0, ""
+	}
+
+	if retryAfter := resp.Header.Get("Retry-After"); retryAfter != "" {
+		seconds, err := strconv.Atoi(retryAfter)
+		if err == nil {
+			return time.Duration(seconds) * time.Second, "secondary rate limit exceeded"
+		}
+	}
+
+	if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+		reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64)
+		if err == nil {
+			// add a second to avoid being a bit too early
+			wait := time.Until(time.Unix(reset, 0)) + time.Second
+			return max(wait, 0), "rate limit exceeded"
+		}
+	}
+
+	// secondary rate limits and abuse detection are not always reported with a Retry-After header,
+	// GitHub recommends to wait at least one minute in this case.
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return 0, ""
+	}
+
+	message := strings.ToLower(string(body))
+	if strings.Contains(message, "secondary rate limit") || strings.Contains(message, "abuse") {
+		return max(backoff, time.Minute), "secondary rate limit exceeded"
+	}
+
+	return 0, ""
+}
+
+// sleep waits for the given duration, or until the context is canceled.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+type waitNotifierKey struct{}
+
+// WaitNotifier is called when a request waits before being retried by [RateLimitTransport].
+type WaitNotifier func(wait time.Duration, reason string)
+
+// WithWaitNotifier returns a copy of ctx in which the requests made report their waits to notify.
+func WithWaitNotifier(ctx context.Context, notify WaitNotifier) context.Context {
+	return context.WithValue(ctx, waitNotifierKey{}, notify)
+}
+
+func notifyWait(ctx context.Context, wait time.Duration, reason string) {
+	notify, ok := ctx.Value(waitNotifierKey{}).(WaitNotifier)
+	if ok && notify != nil {
+		notify(wait, reason)
+	}
+}
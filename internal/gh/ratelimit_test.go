@@ -0,0 +1,126 @@
+package gh
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestRateLimitTransport(t *testing.T) {
+	tests := []struct {
+		name       string
+		failures   func(w http.ResponseWriter)
+		expected   int
+		wantReason string
+	}{
+		{
+			name: "Server error",
+			failures: func(w http.ResponseWriter) {
+				w.WriteHeader(http.StatusBadGateway)
+			},
+			expected:   http.StatusOK,
+			wantReason: "server error 502",
+		},
+		{
+			name: "Retry-After",
+			failures: func(w http.ResponseWriter) {
+				w.Header().Set("Retry-After", "0")
+				w.WriteHeader(http.StatusForbidden)
+			},
+			expected:   http.StatusOK,
+			wantReason: "secondary rate limit exceeded",
+		},
+		{
+			name: "Primary rate limit",
+			failures: func(w http.ResponseWriter) {
+				w.Header().Set("X-RateLimit-Remaining", "0")
+				w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(-time.Minute).Unix(), 10))
+				w.WriteHeader(http.StatusForbidden)
+			},
+			expected:   http.StatusOK,
+			wantReason: "rate limit exceeded",
+		},
+		{
+			name: "Not found",
+			failures: func(w http.ResponseWriter) {
+				w.WriteHeader(http.StatusNotFound)
+			},
+			expected: http.StatusNotFound,
+		},
+		{
+			name: "Forbidden",
+			failures: func(w http.ResponseWriter) {
+				w.WriteHeader(http.StatusForbidden)
+				fmt.Fprint(w, `{"message": "Resource not accessible by integration"}`)
+			},
+			expected: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests int
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				requests++
+				if requests == 1 {
+					tt.failures(w)
+					return
+				}
+				fmt.Fprint(w, "[]")
+			}))
+			defer srv.Close()
+
+			var reason string
+			ctx := WithWaitNotifier(t.Context(), func(_ time.Duration, r string) {
+				reason = r
+			})
+
+			transport := &RateLimitTransport{MaxRetries: 2, Backoff: time.Millisecond}
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, http.NoBody)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			resp, err := transport.RoundTrip(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != tt.expected {
+				t.Errorf("expected status %d, got %d", tt.expected, resp.StatusCode)
+			}
+			if reason != tt.wantReason {
+				t.Errorf("expected reason %q, got %q", tt.wantReason, reason)
+			}
+		})
+	}
+}
+
+func TestRateLimitTransportCanceled(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Retry-After", "3600")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(t.Context())
+	ctx = WithWaitNotifier(ctx, func(time.Duration, string) {
+		cancel()
+	})
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, http.NoBody)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = NewRateLimitTransport(nil).RoundTrip(req)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected %v, got %v", context.Canceled, err)
+	}
+}
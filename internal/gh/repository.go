@@ -1,6 +1,7 @@
 package gh
 
 import (
+	"github.com/cli/go-gh/v2/pkg/auth"
 	"github.com/cli/go-gh/v2/pkg/repository"
 )
 
@@ -11,3 +12,19 @@ type Repository = repository.Repository
 func CurrentRepository() (Repository, error) {
 	return repository.Current()
 }
+
+// DefaultHost returns the host used by default, usually "github.com".
+func DefaultHost() string {
+	host, _ := auth.DefaultHost()
+	return host
+}
+
+// ParseRepository parses a repository name such as "owner/name" or "host/owner/name".
+func ParseRepository(name string) (Repository, error) {
+	return repository.Parse(name)
+}
+
+// RepositoryName returns the "owner/name" representation of the repository.
+func RepositoryName(repo Repository) string {
+	return repo.Owner + "/" + repo.Name
+}
@@ -0,0 +1,90 @@
+package github
+
+import (
+	"strings"
+
+	"github.com/google/go-github/v74/github"
+)
+
+// Actor is the GraphQL representation of a user, a bot or an organization.
+type Actor struct {
+	Typename string `json:"__typename"`
+	Login    string `json:"login"`
+	Name     string `json:"name"`
+}
+
+// User converts the actor into the [User] returned by the REST API.
+//
+// Bots are suffixed with "[bot]" in the REST API, but not in the GraphQL API.
+func (a *Actor) User() User {
+	if a == nil {
+		return User{}
+	}
+
+	login := a.Login
+	if a.Typename == "Bot" && !strings.HasSuffix(login, "[bot]") {
+		login += "[bot]"
+	}
+
+	u := User{User: github.User{Login: &login}}
+	if a.Typename != "" {
+		u.Type = &a.Typename
+	}
+	if a.Name != "" {
+		u.Name = &a.Name
+	}
+	return u
+}
+
+// ReactionNode is the GraphQL representation of a [Reaction].
+type ReactionNode struct {
+	Content   string `json:"content"`
+	CreatedAt Time   `json:"createdAt"`
+	User      *Actor `json:"user"`
+}
+
+// Reaction converts the node into the [Reaction] returned by the REST API.
+func (r ReactionNode) Reaction() Reaction {
+	return Reaction{
+		User:      r.User.User(),
+		Content:   reactionContent(r.Content),
+		CreatedAt: r.CreatedAt,
+	}
+}
+
+// ReactionConnection is the GraphQL representation of the reactions of a post.
+type ReactionConnection struct {
+	TotalCount int `json:"totalCount"`
+	PageInfo   struct {
+		HasNextPage bool   `json:"hasNextPage"`
+		EndCursor   string `json:"endCursor"`
+	} `json:"pageInfo"`
+	Nodes []ReactionNode `json:"nodes"`
+}
+
+// Complete reports whether all the reactions were retrieved.
+func (c ReactionConnection) Complete() bool {
+	return len(c.Nodes) >= c.TotalCount
+}
+
+// Reactions converts the nodes into the [Reaction] returned by the REST API.
+func (c ReactionConnection) Reactions() []Reaction {
+	reactions := make([]Reaction, 0, len(c.Nodes))
+	for _, node := range c.Nodes {
+		reactions = append(reactions, node.Reaction())
+	}
+	return reactions
+}
+
+// reactionContent converts the GraphQL ReactionContent enum into the content used by the REST API.
+func reactionContent(content string) string {
+	switch content {
+	case "THUMBS_UP":
+		return "+1"
+	case "THUMBS_DOWN":
+		return "-1"
+	default:
+		// EYES, HEART, LAUGH, HOORAY, CONFUSED, ROCKET
+		return strings.ToLower(content)
+	}
+}
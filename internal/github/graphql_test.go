@@ -0,0 +1,114 @@
+package github
+
+import "testing"
+
+func TestActorUser(t *testing.T) {
+	tests := []struct {
+		name         string
+		actor        *Actor
+		expected     string
+		expectedType string
+		expectedName string
+	}{
+		{"User", &Actor{Typename: "User", Login: "octocat", Name: "The Octocat"}, "octocat", "User", "The Octocat"},
+		{"User without name", &Actor{Typename: "User", Login: "hubot"}, "hubot", "User", ""},
+		{"Bot", &Actor{Typename: "Bot", Login: "dependabot"}, "dependabot[bot]", "Bot", ""},
+		{"Bot already suffixed", &Actor{Typename: "Bot", Login: "renovate[bot]"}, "renovate[bot]", "Bot", ""},
+		{"Organization", &Actor{Typename: "Organization", Login: "github"}, "github", "Organization", ""},
+		{"Deleted user", nil, "", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.actor.User()
+			if got.GetLogin() != tt.expected {
+				t.Errorf("expected login %q, got %q", tt.expected, got.GetLogin())
+			}
+			if got.GetType() != tt.expectedType {
+				t.Errorf("expected type %q, got %q", tt.expectedType, got.GetType())
+			}
+			if got.GetName() != tt.expectedName {
+				t.Errorf("expected name %q, got %q", tt.expectedName, got.GetName())
+			}
+		})
+	}
+
+	if bot := (&Actor{Typename: "Bot", Login: "dependabot"}).User(); !bot.IsBot() {
+		t.Error("expected the bot to be detected as the REST API ones")
+	}
+}
+
+func TestReactionContent(t *testing.T) {
+	tests := []struct {
+		content  string
+		expected string
+	}{
+		{"THUMBS_UP", "+1"},
+		{"THUMBS_DOWN", "-1"},
+		{"LAUGH", "laugh"},
+		{"HOORAY", "hooray"},
+		{"CONFUSED", "confused"},
+		{"HEART", "heart"},
+		{"ROCKET", "rocket"},
+		{"EYES", "eyes"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.content, func(t *testing.T) {
+			got := reactionContent(tt.content)
+			if got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+
+			// the content is the one known by the REST API
+			if _, err := ParseReactionContent(got); err != nil {
+				t.Errorf("unexpected error for %q: %v", got, err)
+			}
+		})
+	}
+}
+
+func TestReactionConnectionComplete(t *testing.T) {
+	nodes := func(n int) []ReactionNode {
+		return make([]ReactionNode, n)
+	}
+
+	tests := []struct {
+		name       string
+		connection ReactionConnection
+		expected   bool
+	}{
+		{"No reactions", ReactionConnection{}, true},
+		{"All the reactions", ReactionConnection{TotalCount: 2, Nodes: nodes(2)}, true},
+		{"First page only", ReactionConnection{TotalCount: 150, Nodes: nodes(100)}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.connection.Complete(); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestReactionConnectionReactions(t *testing.T) {
+	connection := ReactionConnection{
+		TotalCount: 2,
+		Nodes: []ReactionNode{
+			{Content: "THUMBS_UP", User: &Actor{Typename: "User", Login: "octocat"}},
+			{Content: "ROCKET", User: &Actor{Typename: "Bot", Login: "dependabot"}},
+		},
+	}
+
+	got := connection.Reactions()
+	if len(got) != 2 {
+		t.Fatalf("expected 2 reactions, got %d", len(got))
+	}
+	if got[0].Content != "+1" || got[0].User.GetLogin() != "octocat" {
+		t.Errorf("unexpected first reaction %+v", got[0])
+	}
+	if got[1].Content != "rocket" || got[1].User.GetLogin() != "dependabot[bot]" {
+		t.Errorf("unexpected second reaction %+v", got[1])
+	}
+}
@@ -1,6 +1,8 @@
 package github
 
 import (
+	"encoding/json"
+	"fmt"
 	"strings"
 	"time"
 
@@ -23,23 +25,27 @@ func (u User) GitHubURL() string {
 }
 
 // IsBot reports whether the user is a bot account.
+//
+// The GitHub API reports the type of the users, the GitHub Apps are of type "Bot"
+// and their login ends with "[bot]".
 func (u User) IsBot() bool {
-	if u.Login == nil {
-		return false
-	}
-
-	switch strings.ToLower(*u.Login) {
-	case
-		"coderabbitai[bot]",
-		"dependabot[bot]",
-		"github-actions[bot]",
-		"renovate[bot]", // renovate is the old name for mend
-		"mend[bot]",     // mend is the new name for renovate
-		"codecov-commenter":
-		return true
-	}
+	return u.GetType() == "Bot" || strings.HasSuffix(u.GetLogin(), "[bot]")
+}
 
-	return false
+// MarshalJSON encodes the user with the fields relevant to a report,
+// instead of the full GitHub API representation.
+//
+// It satisfies the [json.Marshaler] interface.
+func (u User) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Login string `json:"login"`
+		Name  string `json:"name,omitempty"`
+		URL   string `json:"url"`
+	}{
+		Login: u.GetLogin(),
+		Name:  u.GetName(),
+		URL:   u.GitHubURL(),
+	})
 }
 
 func (u User) String() string {
@@ -99,3 +105,19 @@ func (r Reaction) Type() string {
 		return "🤷" + " unknown reaction " + r.Content
 	}
 }
+
+// ReactionContents lists the contents of the reactions supported by GitHub.
+var ReactionContents = []string{"+1", "-1", "laugh", "confused", "heart", "hooray", "rocket", "eyes"}
+
+// ParseReactionContent returns the content of a reaction from its name, such as "+1" or "heart",
+// or from the emoji returned by [Reaction.Type].
+func ParseReactionContent(value string) (string, error) {
+	// the emoji may be typed with or without the variation selector, as in ❤️ or ❤
+	value = strings.TrimSuffix(strings.TrimSpace(value), "\uFE0F")
+	for _, content := range ReactionContents {
+		if strings.EqualFold(value, content) || value == strings.TrimSuffix(Reaction{Content: content}.Type(), "\uFE0F") {
+			return content, nil
+		}
+	}
+	return "", fmt.Errorf("unknown reaction %q, expected one of %v", value, ReactionContents)
+}
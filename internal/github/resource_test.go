@@ -0,0 +1,53 @@
+package github
+
+import "testing"
+
+func TestParseReactionContent(t *testing.T) {
+	for value, expected := range map[string]string{
+		"+1":     "+1",
+		"Heart":  "heart",
+		"👎":      "-1",
+		"❤️":     "heart",
+		"❤":      "heart",
+		" 🚀 ":    "rocket",
+		"hooray": "hooray",
+	} {
+		got, err := ParseReactionContent(value)
+		if err != nil {
+			t.Errorf("unexpected error for %q: %v", value, err)
+			continue
+		}
+		if got != expected {
+			t.Errorf("expected %q for %q, got %q", expected, value, got)
+		}
+	}
+
+	if _, err := ParseReactionContent("tada"); err == nil {
+		t.Error("expected an error for an unknown reaction")
+	}
+}
+
+func TestUserIsBot(t *testing.T) {
+	newUser := func(login, userType string) User {
+		var u User
+		u.Login = &login
+		u.Type = &userType
+		return u
+	}
+
+	for _, tc := range []struct {
+		user     User
+		expected bool
+	}{
+		{newUser("octocat", "User"), false},
+		{newUser("release-bot", "User"), false},
+		{newUser("dependabot[bot]", "Bot"), true},
+		{newUser("dependabot[bot]", ""), true},
+		{newUser("copilot", "Bot"), true},
+		{User{}, false},
+	} {
+		if got := tc.user.IsBot(); got != tc.expected {
+			t.Errorf("expected IsBot() to be %v for %s, got %v", tc.expected, tc.user, got)
+		}
+	}
+}
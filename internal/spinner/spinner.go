@@ -9,7 +9,9 @@ import (
 // Spinner is a terminal spinner that provides visual feedback during long-running operations.
 type Spinner struct {
 	out      io.Writer
+	ctx      context.Context // set by Start, stops the animation when canceled
 	done     chan struct{}
+	stopped  chan struct{} // closed when the animation stops
 	tick     chan string
 	maxChars int
 }
@@ -17,18 +19,22 @@ type Spinner struct {
 // New creates a new Spinner that writes to the given output.
 func New(out io.Writer) *Spinner {
 	s := &Spinner{
-		out:  out,
-		done: make(chan struct{}),
-		tick: make(chan string),
+		out:     out,
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+		tick:    make(chan string),
 	}
 	return s
 }
 
 // Start begins the spinner animation with the initial message.
 func (s *Spinner) Start(ctx context.Context, str string) {
+	s.ctx = ctx
 	s.print(str)
 	spinningCharacters := []rune("⣾⣽⣻⢿⡿⣟⣯⣷")
 	go func() {
+		defer close(s.stopped)
+
 		var pos int
 		for {
 			select {
@@ -47,14 +53,24 @@ func (s *Spinner) Start(ctx context.Context, str string) {
 }
 
 // Progress updates the spinner with a new message.
+//
+// It doesn't block once the context passed to [Spinner.Start] is canceled.
 func (s *Spinner) Progress(format string, args ...any) {
-	s.tick <- fmt.Sprintf(format, args...)
+	select {
+	case s.tick <- fmt.Sprintf(format, args...):
+	case <-s.ctx.Done():
+	}
 }
 
 // Done stops the spinner and prints the final message.
+//
+// The animation is stopped first, so the final message is the last one printed.
 func (s *Spinner) Done(format string, args ...any) {
-	s.print("\r" + fmt.Sprintf(format, args...) + "\n")
 	close(s.done)
+	if s.ctx != nil {
+		<-s.stopped
+	}
+	s.print("\r" + fmt.Sprintf(format, args...) + "\n")
 	close(s.tick)
 }
 
@@ -0,0 +1,26 @@
+package spinner
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestSpinnerDone(t *testing.T) {
+	var out bytes.Buffer
+
+	s := New(&out)
+	s.Start(t.Context(), "starting")
+	for i := range 10 {
+		s.Progress("step %d", i)
+	}
+	s.Done("done")
+
+	got := out.String()
+	if !strings.HasSuffix(got, "\rdone\n") {
+		t.Errorf("expected the final message to be printed last, got %q", got)
+	}
+	if !strings.Contains(got, "step 9") {
+		t.Errorf("expected the progress to be printed, got %q", got)
+	}
+}
@@ -0,0 +1,197 @@
+package timeago
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DateRange is a time window starting at Since, and ending before Until.
+//
+// A zero Until means the range has no upper bound.
+type DateRange struct {
+	Since RelativeDate
+	Until RelativeDate
+}
+
+// ErrMissingStart is returned when a range has no lower bound, such as "..2024-03-31".
+var ErrMissingStart = errors.New("the start of the range is missing")
+
+// ErrUntilAlreadySet is returned when the upper bound of a range would be set twice,
+// by a range and by the value returned by [DateRange.UntilValue].
+var ErrUntilAlreadySet = errors.New("the end of the range is already set")
+
+// String returns the string representation of the DateRange.
+//
+// It satisfies the [flag.Value] and [fmt.Stringer] interface.
+func (r DateRange) String() string {
+	if r.Until.IsZero() {
+		return r.Since.String()
+	}
+	return fmt.Sprintf("%s..%s", r.Since.Format(time.RFC3339), r.Until.Format(time.RFC3339))
+}
+
+// Set sets the DateRange from a string value.
+//
+// The value is either a date accepted by [RelativeDate.Set], that only sets the lower bound,
+// or a range such as "2024-01-01..2024-03-31", "2024-01..2024-02", "Q1-2024" or "last-month",
+// that sets both bounds. The end of a range is included: "2024-01-01..2024-03-31" ends
+// at the end of March 31st. The end of a range can be omitted, as in "2024-01-01..", but not its start.
+//
+// A range cannot set the upper bound when it's already set, see [ErrUntilAlreadySet].
+//
+// It satisfies the [flag.Value] interface.
+func (r *DateRange) Set(value string) error {
+	now := time.Now()
+
+	if start, end, found := strings.Cut(value, ".."); found {
+		if start == "" {
+			return ErrMissingStart
+		}
+
+		since, err := parseStartDate(start, now)
+		if err != nil {
+			return err
+		}
+
+		var until time.Time
+		if end != "" {
+			until, err = parseEndDate(end, now)
+			if err != nil {
+				return err
+			}
+		}
+
+		return r.set(since, until)
+	}
+
+	if since, until, err := parsePeriod(value, now); err == nil {
+		return r.set(since, until)
+	}
+
+	return r.Since.Set(value)
+}
+
+var _ flag.Value = (*DateRange)(nil)
+
+// Contains reports whether t is in the range.
+func (r DateRange) Contains(t time.Time) bool {
+	return !t.Before(r.Since.Time) && (r.Until.IsZero() || t.Before(r.Until.Time))
+}
+
+// UntilValue returns a [flag.Value] that only sets the upper bound of the range.
+//
+// The date is included, so "2024-03-31" sets the upper bound to the end of March 31st.
+// It fails with [ErrUntilAlreadySet] when the upper bound is already set.
+func (r *DateRange) UntilValue() flag.Value {
+	return &untilValue{r: r}
+}
+
+// set sets the bounds of the range, a zero until keeps the upper bound.
+func (r *DateRange) set(since, until time.Time) error {
+	if !until.IsZero() && !r.Until.IsZero() {
+		return ErrUntilAlreadySet
+	}
+
+	r.Since = NewRelativeDate(since)
+	if !until.IsZero() {
+		r.Until = NewRelativeDate(until)
+	}
+	return nil
+}
+
+type untilValue struct {
+	r *DateRange
+}
+
+func (u *untilValue) String() string {
+	if u.r == nil || u.r.Until.IsZero() {
+		return ""
+	}
+	return u.r.Until.String()
+}
+
+func (u *untilValue) Set(value string) error {
+	if !u.r.Until.IsZero() {
+		return ErrUntilAlreadySet
+	}
+
+	t, err := parseEndDate(value, time.Now())
+	if err != nil {
+		return err
+	}
+	u.r.Until = NewRelativeDate(t)
+	return nil
+}
+
+// parseStartDate parses the lower bound of a range.
+func parseStartDate(value string, now time.Time) (time.Time, error) {
+	if start, _, err := parsePeriod(value, now); err == nil {
+		return start, nil
+	}
+	return parseDate(value)
+}
+
+// parseEndDate parses the upper bound of a range, the day, month, quarter or year is included.
+func parseEndDate(value string, now time.Time) (time.Time, error) {
+	if t, err := time.ParseInLocation(time.DateOnly, value, time.UTC); err == nil {
+		return t.AddDate(0, 0, 1), nil
+	}
+	if _, end, err := parsePeriod(value, now); err == nil {
+		return end, nil
+	}
+	return parseDate(value)
+}
+
+// parsePeriod parses a calendar period such as "2024", "2024-03", "Q1-2024", "2024-Q1",
+// "last-month" or "this-year", and returns its start and its end (excluded), in UTC.
+func parsePeriod(value string, now time.Time) (start, end time.Time, err error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	now = now.UTC()
+
+	thisMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	thisQuarter := thisMonth.AddDate(0, -int(now.Month()-1)%3, 0)
+	thisYear := time.Date(now.Year(), time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	switch value {
+	case "this-month":
+		return thisMonth, thisMonth.AddDate(0, 1, 0), nil
+	case "last-month":
+		return thisMonth.AddDate(0, -1, 0), thisMonth, nil
+	case "this-quarter":
+		return thisQuarter, thisQuarter.AddDate(0, 3, 0), nil
+	case "last-quarter":
+		return thisQuarter.AddDate(0, -3, 0), thisQuarter, nil
+	case "this-year":
+		return thisYear, thisYear.AddDate(1, 0, 0), nil
+	case "last-year":
+		return thisYear.AddDate(-1, 0, 0), thisYear, nil
+	}
+
+	if quarter, year, ok := parseQuarter(value); ok {
+		start = time.Date(year, time.Month(3*(quarter-1)+1), 1, 0, 0, 0, 0, time.UTC)
+		return start, start.AddDate(0, 3, 0), nil
+	}
+
+	if t, err := time.ParseInLocation("2006-01", value, time.UTC); err == nil {
+		return t, t.AddDate(0, 1, 0), nil
+	}
+
+	if t, err := time.ParseInLocation("2006", value, time.UTC); err == nil {
+		return t, t.AddDate(1, 0, 0), nil
+	}
+
+	return time.Time{}, time.Time{}, ErrUnsupportedDateFormat
+}
+
+// parseQuarter parses a quarter written as "q1-2024" or "2024-q1".
+func parseQuarter(value string) (quarter, year int, ok bool) {
+	if _, err := fmt.Sscanf(value, "q%d-%d", &quarter, &year); err != nil || value != fmt.Sprintf("q%d-%d", quarter, year) {
+		if _, err := fmt.Sscanf(value, "%d-q%d", &year, &quarter); err != nil || value != fmt.Sprintf("%d-q%d", year, quarter) {
+			return 0, 0, false
+		}
+	}
+	return quarter, year, quarter >= 1 && quarter <= 4
+}
@@ -0,0 +1,120 @@
+package timeago
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParsePeriod(t *testing.T) {
+	now := time.Date(2024, 5, 15, 10, 0, 0, 0, time.UTC)
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		input string
+		start time.Time
+		end   time.Time
+	}{
+		{"2024", date(2024, 1, 1), date(2025, 1, 1)},
+		{"2024-03", date(2024, 3, 1), date(2024, 4, 1)},
+		{"Q1-2024", date(2024, 1, 1), date(2024, 4, 1)},
+		{"2023-q4", date(2023, 10, 1), date(2024, 1, 1)},
+		{"this-month", date(2024, 5, 1), date(2024, 6, 1)},
+		{"last-month", date(2024, 4, 1), date(2024, 5, 1)},
+		{"this-quarter", date(2024, 4, 1), date(2024, 7, 1)},
+		{"last-quarter", date(2024, 1, 1), date(2024, 4, 1)},
+		{"last-year", date(2023, 1, 1), date(2024, 1, 1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			start, end, err := parsePeriod(tt.input, now)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !start.Equal(tt.start) || !end.Equal(tt.end) {
+				t.Errorf("expected %v..%v, got %v..%v", tt.start, tt.end, start, end)
+			}
+		})
+	}
+
+	for _, input := range []string{"Q5-2024", "q1-2024x", "2024-01-02", "3d"} {
+		if _, _, err := parsePeriod(input, now); err == nil {
+			t.Errorf("expected an error for %q", input)
+		}
+	}
+}
+
+func TestDateRangeSet(t *testing.T) {
+	var r DateRange
+	if err := r.Set("2024-01-01..2024-03-31"); err != nil {
+		t.Fatal(err)
+	}
+
+	expectedSince := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	expectedUntil := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+	if !r.Since.Equal(expectedSince) || !r.Until.Equal(expectedUntil) {
+		t.Errorf("expected %v..%v, got %v", expectedSince, expectedUntil, r)
+	}
+
+	if !r.Contains(time.Date(2024, 3, 31, 23, 0, 0, 0, time.UTC)) {
+		t.Error("expected the last day to be in the range")
+	}
+	if r.Contains(expectedUntil) || r.Contains(expectedSince.Add(-time.Second)) {
+		t.Error("expected the dates out of the range to be excluded")
+	}
+
+	// a single date only sets the lower bound
+	if err := r.Set("2023-06-01"); err != nil {
+		t.Fatal(err)
+	}
+	if !r.Until.Equal(expectedUntil) {
+		t.Errorf("expected the upper bound to be kept, got %v", r.Until)
+	}
+
+	var until DateRange
+	if err := until.UntilValue().Set("2023-12-31"); err != nil {
+		t.Fatal(err)
+	}
+	if expected := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC); !until.Until.Equal(expected) {
+		t.Errorf("expected %v, got %v", expected, until.Until)
+	}
+
+	// an open end keeps the upper bound
+	if err := until.Set("2023-06-01.."); err != nil {
+		t.Fatal(err)
+	}
+	if expected := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC); !until.Until.Equal(expected) {
+		t.Errorf("expected the upper bound to be kept, got %v", until.Until)
+	}
+
+	if err := r.Set("2024-01-01..nope"); err == nil {
+		t.Error("expected an error for an invalid range")
+	}
+}
+
+func TestDateRangeSetErrors(t *testing.T) {
+	var r DateRange
+	if err := r.Set("..2024-03-31"); !errors.Is(err, ErrMissingStart) {
+		t.Errorf("expected %v, got %v", ErrMissingStart, err)
+	}
+
+	// the upper bound cannot be set twice, whatever the order of the flags
+	r = DateRange{}
+	if err := r.UntilValue().Set("2024-03-31"); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Set("Q1-2024"); !errors.Is(err, ErrUntilAlreadySet) {
+		t.Errorf("expected %v, got %v", ErrUntilAlreadySet, err)
+	}
+
+	r = DateRange{}
+	if err := r.Set("Q1-2024"); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.UntilValue().Set("2024-03-31"); !errors.Is(err, ErrUntilAlreadySet) {
+		t.Errorf("expected %v, got %v", ErrUntilAlreadySet, err)
+	}
+}
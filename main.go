@@ -8,12 +8,15 @@ import (
 	"flag"
 	"fmt"
 	"maps"
+	"net/http"
 	"net/url"
 	"os"
 	"os/signal"
+	"path"
 	"slices"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/ccoVeille/gh-reaction/internal/gh"
@@ -28,36 +31,96 @@ const (
 	PostTypeIssue       PostType = "issue"
 	PostTypePullRequest PostType = "pull_request"
 	PostTypeComment     PostType = "comment"
+
+	PostTypeReviewComment PostType = "review_comment"
+
+	PostTypeDiscussion        PostType = "discussion"
+	PostTypeDiscussionComment PostType = "discussion_comment"
+
+	PostTypeCommitComment PostType = "commit_comment"
+	PostTypeRelease       PostType = "release"
 )
 
+// reactionsEndpoints maps the post types to the REST endpoint listing their reactions, relative to the repository.
+//
+// The discussions are missing, their reactions are fetched with the GraphQL API, see fetchDiscussions.
+var reactionsEndpoints = map[PostType]string{
+	PostTypeIssue:         "issues/%s/reactions",
+	PostTypePullRequest:   "issues/%s/reactions",
+	PostTypeComment:       "issues/comments/%s/reactions",
+	PostTypeReviewComment: "pulls/comments/%s/reactions",
+	PostTypeCommitComment: "comments/%s/reactions",
+	PostTypeRelease:       "releases/%s/reactions",
+}
+
 type Post struct {
-	Type    PostType
-	Date    github.Time
-	Content string
-	Author  github.User
-	Link    string
-	ID      string
-}
-
-func (p Post) FetchReactions(ctx context.Context, client *gh.RESTClient, repo gh.Repository) (Reactions, error) {
-	var uri string
-	if p.Type == PostTypeComment {
-		uri = fmt.Sprintf("repos/%s/%s/issues/comments/%s/reactions?per_page=100", repo.Owner, repo.Name, p.ID)
-	} else {
-		uri = fmt.Sprintf("repos/%s/%s/issues/%s/reactions?per_page=100", repo.Owner, repo.Name, p.ID)
+	// Repository is the repository where the post was made, it's encoded as "owner/name".
+	Repository gh.Repository `json:"-"`
+
+	Type PostType `json:"type"`
+	// Date is the creation or the update date of the post, as selected with -date-field,
+	// so it's the same as CreatedAt or UpdatedAt depending on the run.
+	Date      github.Time `json:"date"`
+	CreatedAt github.Time `json:"created_at"`
+	UpdatedAt github.Time `json:"updated_at"`
+	Content   string      `json:"content"`
+	Author    github.User `json:"author"`
+	Link      string      `json:"link"`
+	ID        string      `json:"id"`
+
+	// reactions are the reactions fetched along with the post, by the GraphQL backend.
+	reactions []github.Reaction
+	// reactionsFetched reports whether reactions holds all the reactions of the post.
+	reactionsFetched bool
+}
+
+// MarshalJSON encodes the post with its repository name and its content preview.
+//
+// It satisfies the [json.Marshaler] interface.
+func (p Post) MarshalJSON() ([]byte, error) {
+	type post Post // avoid infinite recursion
+	return marshalJSON(struct {
+		Repository string `json:"repository"`
+		post
+		Preview string `json:"preview"`
+	}{
+		Repository: gh.RepositoryName(p.Repository),
+		post:       post(p),
+		Preview:    p.ContentPreview(),
+	})
+}
+
+func (p Post) FetchReactions(ctx context.Context, client *gh.RESTClient) (Reactions, error) {
+	if p.reactionsFetched {
+		var results Reactions
+		post := p
+		post.reactions = nil // no need to keep them with each reaction
+		for _, reaction := range p.reactions {
+			results = append(results, ReactionTo{
+				Post:     post,
+				Reaction: reaction,
+			})
+		}
+		return results, nil
 	}
 
-	var reactions []github.Reaction
-	if err := client.Get(ctx, uri, &reactions); err != nil {
+	uri, err := p.reactionsURI()
+	if err != nil {
 		return nil, err
 	}
 
 	var results Reactions
-	for _, reaction := range reactions {
-		results = append(results, ReactionTo{
-			Post:     p,
-			Reaction: reaction,
-		})
+	for reactions, err := range gh.Paginate[github.Reaction](ctx, client, uri) {
+		if err != nil {
+			return nil, err
+		}
+
+		for _, reaction := range reactions {
+			results = append(results, ReactionTo{
+				Post:     p,
+				Reaction: reaction,
+			})
+		}
 	}
 
 	return results, nil
@@ -108,47 +171,94 @@ func truncateString(content string, maxLen int) string {
 	return cleanString(string(str[:maxLen])) + " …"
 }
 
+// restIssue is an issue or a pull request returned by the REST API, by the issues and the search endpoints.
+//
+// TODO use github.Issue
+type restIssue struct {
+	Title         string      `json:"title"`
+	CreatedAt     github.Time `json:"created_at"`
+	UpdatedAt     github.Time `json:"updated_at"`
+	Author        github.User `json:"user"`
+	PullRequest   *struct{}   `json:"pull_request,omitempty"`
+	Number        int         `json:"number"`
+	Link          string      `json:"html_url"`
+	RepositoryURL string      `json:"repository_url"`
+}
+
+// repository returns the repository of the issue, from its API URL such as https://api.github.com/repos/owner/name.
+func (i restIssue) repository() (gh.Repository, error) {
+	u, err := url.Parse(i.RepositoryURL)
+	if err != nil {
+		return gh.Repository{}, err
+	}
+
+	name := strings.TrimPrefix(u.Path, "/api/v3") // GitHub Enterprise Server
+	name = strings.TrimPrefix(name, "/repos/")
+
+	host := u.Host
+	if host == "api.github.com" {
+		host = "github.com"
+	}
+
+	return gh.ParseRepository(host + "/" + name)
+}
+
+// restComment is a comment returned by the REST API, on an issue, a pull request or a commit.
+//
+// TODO use github.Comment
+type restComment struct {
+	Body      string      `json:"body"`
+	CreatedAt github.Time `json:"created_at"`
+	UpdatedAt github.Time `json:"updated_at"`
+	Author    github.User `json:"user"`
+	Link      string      `json:"html_url"`
+	ID        int         `json:"id"`
+}
+
+// startSpinner starts a spinner on stderr with the message, the returned context reports on it
+// the waits of the rate limited requests.
+func startSpinner(ctx context.Context, msg string) (context.Context, *spinner.Spinner) {
+	spin := spinner.New(os.Stderr)
+	spin.Start(ctx, msg)
+
+	ctx = gh.WithWaitNotifier(ctx, func(wait time.Duration, reason string) {
+		spin.Progress("⏳ %s, waiting %s", reason, wait.Round(time.Second))
+	})
+
+	return ctx, spin
+}
+
 // Fetch messages posted by the user in the current repository
-func fetchPosts(ctx context.Context, client *gh.RESTClient, gitHubRepo gh.Repository, minDate timeago.RelativeDate) ([]Post, error) {
+func fetchPosts(ctx context.Context, client *gh.RESTClient, graphqlClient *gh.GraphQLClient, gitHubRepo gh.Repository, minDate timeago.RelativeDate, author string) ([]Post, error) {
 	suffix := fmt.Sprintf("on github.com/%s/%s since %s", gitHubRepo.Owner, gitHubRepo.Name, minDate.String())
+	if author != "" {
+		suffix = fmt.Sprintf("of %s %s", author, suffix)
+	}
 
-	fmt.Printf("Looking for posts %s\n", suffix)
+	fmt.Fprintf(os.Stderr, "Looking for posts %s\n", suffix)
 
 	var posts []Post
 
-	spin := spinner.New(os.Stdout)
-	spin.Start(ctx, "fetching posts")
+	ctx, spin := startSpinner(ctx, "fetching posts")
 
 	// Fetch issues and PRs created by the user in the repository
-	page := 1
-	for {
-		// TODO use github.Issue
-		userIssues := []struct {
-			Title       string      `json:"title"`
-			UpdatedAt   github.Time `json:"updated_at"`
-			Author      github.User `json:"user"`
-			PullRequest *struct{}   `json:"pull_request,omitempty"`
-			Number      int         `json:"number"`
-		}{}
-
-		q := url.Values{
-			"page":      []string{strconv.Itoa(page)},
-			"per_page":  []string{"100"},
-			"sort":      []string{"commented"},
-			"direction": []string{"desc"},
-		}
-
-		if !minDate.IsZero() {
-			q.Set("since", minDate.Format(time.RFC3339))
-		}
-
-		uri := fmt.Sprintf("repos/%s/%s/issues?%s", gitHubRepo.Owner, gitHubRepo.Name, q.Encode())
-		if err := client.Get(ctx, uri, &userIssues); err != nil {
+	q := url.Values{
+		"per_page":  []string{"100"},
+		"sort":      []string{"commented"},
+		"direction": []string{"desc"},
+	}
+	if !minDate.IsZero() {
+		q.Set("since", minDate.Format(time.RFC3339))
+	}
+	if author != "" {
+		q.Set("creator", author)
+	}
+
+	uri := fmt.Sprintf("repos/%s/%s/issues?%s", gitHubRepo.Owner, gitHubRepo.Name, q.Encode())
+	for userIssues, err := range gh.Paginate[restIssue](ctx, client, uri) {
+		if err != nil {
 			return nil, err
 		}
-		if len(userIssues) == 0 {
-			break
-		}
 		for _, issue := range userIssues {
 			postType := PostTypeIssue
 			if issue.PullRequest != nil {
@@ -156,69 +266,276 @@ func fetchPosts(ctx context.Context, client *gh.RESTClient, gitHubRepo gh.Reposi
 			}
 
 			posts = append(posts, Post{
-				Type:    postType,
-				Date:    issue.UpdatedAt,
-				Content: issue.Title,
-				Author:  issue.Author,
-				Link:    fmt.Sprintf("https://github.com/%s/%s/issues/%d", gitHubRepo.Owner, gitHubRepo.Name, issue.Number),
-				ID:      strconv.Itoa(issue.Number),
+				Type:       postType,
+				Repository: gitHubRepo,
+				CreatedAt:  issue.CreatedAt,
+				UpdatedAt:  issue.UpdatedAt,
+				Content:    issue.Title,
+				Author:     issue.Author,
+				Link:       fmt.Sprintf("https://github.com/%s/%s/issues/%d", gitHubRepo.Owner, gitHubRepo.Name, issue.Number),
+				ID:         strconv.Itoa(issue.Number),
 			})
 			spin.Progress("fetched %d posts", len(posts))
 		}
-		page++
+	}
+
+	addPost := func(post Post) {
+		posts = append(posts, post)
+		spin.Progress("fetched %d posts", len(posts))
 	}
 
 	// Fetch comments made by the user in the repository
-	page = 1
-	for {
+	if author != "" {
+		// the comments of the repository cannot be filtered by author,
+		// the comments are fetched through the issues and PRs the user commented on
+		query := fmt.Sprintf("commenter:%s repo:%s", author, gh.RepositoryName(gitHubRepo))
+		err := searchIssues(ctx, client, query, minDate.Time, time.Now().UTC().Truncate(time.Second), func(issue restIssue) error {
+			return fetchIssueComments(ctx, client, gitHubRepo, issue.Number, author, minDate, addPost)
+		})
+		if err != nil {
+			return nil, err
+		}
+	} else if err := fetchComments(ctx, client, gitHubRepo, minDate, author, PostTypeComment, addPost); err != nil {
+		return nil, err
+	}
+
+	// Fetch review comments made by the user on pull requests of the repository
+	if err := fetchComments(ctx, client, gitHubRepo, minDate, author, PostTypeReviewComment, addPost); err != nil {
+		return nil, err
+	}
 
-		// TODO use github.Comment
-		userComments := []struct {
-			Body      string      `json:"body"`
-			UpdatedAt github.Time `json:"updated_at"`
-			Author    github.User `json:"user"`
-			Link      string      `json:"html_url"`
-			ID        int         `json:"id"`
-		}{}
+	// Fetch comments made by the user on commits of the repository
+	if err := fetchComments(ctx, client, gitHubRepo, minDate, author, PostTypeCommitComment, addPost); err != nil {
+		return nil, err
+	}
 
-		q := url.Values{
-			"per_page":  []string{"100"},
-			"sort":      []string{"updated"},
-			"direction": []string{"desc"},
-		}
-		if !minDate.IsZero() {
-			q.Set("since", minDate.Format(time.RFC3339))
+	// Fetch releases published by the user in the repository
+	if err := fetchReleases(ctx, client, gitHubRepo, minDate, author, addPost); err != nil {
+		return nil, err
+	}
+
+	// Fetch discussions and discussion comments made by the user in the repository
+	if err := fetchDiscussions(ctx, graphqlClient, gitHubRepo, minDate, author, addPost); err != nil {
+		return nil, err
+	}
+
+	spin.Done("✔️ fetched %d posts", len(posts))
+
+	// Sort posts by update time in descending order
+	slices.SortFunc(posts, func(a1, a2 Post) int {
+		return a2.UpdatedAt.Compare(a1.UpdatedAt.Time)
+	})
+
+	return posts, nil
+}
+
+// commentsEndpoints maps the comment types to the REST endpoint listing them, relative to the repository.
+var commentsEndpoints = map[PostType]string{
+	PostTypeComment:       "issues/comments",
+	PostTypeReviewComment: "pulls/comments",
+	PostTypeCommitComment: "comments",
+}
+
+// fetchComments fetches the comments of the given type updated since minDate.
+//
+// The commit comments endpoint doesn't support the since parameter, and lists the oldest comments first:
+// the pages are walked from the last one, until a comment created before minDate is found.
+func fetchComments(ctx context.Context, client *gh.RESTClient, gitHubRepo gh.Repository, minDate timeago.RelativeDate, author string, postType PostType, addPost func(Post)) error {
+	endpoint, found := commentsEndpoints[postType]
+	if !found {
+		return fmt.Errorf("no endpoint to fetch the %s posts", postType)
+	}
+
+	q := url.Values{
+		"per_page":  []string{"100"},
+		"sort":      []string{"updated"},
+		"direction": []string{"desc"},
+	}
+	if !minDate.IsZero() {
+		q.Set("since", minDate.Format(time.RFC3339))
+	}
+
+	uri := fmt.Sprintf("repos/%s/%s/%s?%s", gitHubRepo.Owner, gitHubRepo.Name, endpoint, q.Encode())
+	pages := gh.Paginate[restComment](ctx, client, uri)
+	if postType == PostTypeCommitComment {
+		pages = gh.PaginateBackward[restComment](ctx, client, uri)
+	}
+
+	for userComments, err := range pages {
+		if err != nil {
+			return err
 		}
 
-		uri := fmt.Sprintf("repos/%s/%s/issues/comments?page=%d&%s", gitHubRepo.Owner, gitHubRepo.Name, page, q.Encode())
-		if err := client.Get(ctx, uri, &userComments); err != nil {
-			return nil, err
+		var older bool
+		for _, comment := range userComments {
+			if postType == PostTypeCommitComment && comment.CreatedAt.Before(minDate.Time) {
+				older = true
+			}
+			if comment.UpdatedAt.Before(minDate.Time) || !authoredBy(comment.Author, author) {
+				continue
+			}
+
+			addPost(Post{
+				Type:       postType,
+				Repository: gitHubRepo,
+				CreatedAt:  comment.CreatedAt,
+				UpdatedAt:  comment.UpdatedAt,
+				Content:    comment.Body,
+				Author:     comment.Author,
+				Link:       comment.Link,
+				ID:         strconv.Itoa(comment.ID),
+			})
 		}
-		if len(userComments) == 0 {
+
+		if older {
+			// the previous pages are older than minDate
 			break
 		}
-		for _, comment := range userComments {
-			posts = append(posts, Post{
-				Type:    PostTypeComment,
-				Date:    comment.UpdatedAt,
-				Content: comment.Body,
-				Author:  comment.Author,
-				Link:    comment.Link,
-				ID:      strconv.Itoa(comment.ID),
+	}
+
+	return nil
+}
+
+// fetchReleases fetches the releases published since minDate, the drafts are ignored.
+//
+// The releases are listed by creation date, the pages are walked until a release created before minDate is found.
+func fetchReleases(ctx context.Context, client *gh.RESTClient, gitHubRepo gh.Repository, minDate timeago.RelativeDate, author string, addPost func(Post)) error {
+	// TODO use github.RepositoryRelease
+	type release struct {
+		Name        string       `json:"name"`
+		TagName     string       `json:"tag_name"`
+		CreatedAt   github.Time  `json:"created_at"`
+		PublishedAt *github.Time `json:"published_at"`
+		Draft       bool         `json:"draft"`
+		Author      github.User  `json:"author"`
+		Link        string       `json:"html_url"`
+		ID          int          `json:"id"`
+	}
+
+	// the releases are sorted by creation date (descending), but they cannot be filtered by date
+	uri := fmt.Sprintf("repos/%s/%s/releases?per_page=100", gitHubRepo.Owner, gitHubRepo.Name)
+	for releases, err := range gh.Paginate[release](ctx, client, uri) {
+		if err != nil {
+			return err
+		}
+
+		var older bool
+		for _, r := range releases {
+			if r.CreatedAt.Before(minDate.Time) {
+				older = true
+			}
+			if r.Draft || r.PublishedAt == nil {
+				// the drafts are not published yet
+				continue
+			}
+			if r.PublishedAt.Before(minDate.Time) || !authoredBy(r.Author, author) {
+				continue
+			}
+
+			content := r.Name
+			if content == "" {
+				content = r.TagName
+			}
+
+			addPost(Post{
+				Type:       PostTypeRelease,
+				Repository: gitHubRepo,
+				CreatedAt:  *r.PublishedAt,
+				UpdatedAt:  *r.PublishedAt,
+				Content:    content,
+				Author:     r.Author,
+				Link:       r.Link,
+				ID:         strconv.Itoa(r.ID),
 			})
-			spin.Progress("fetched %d posts", len(posts))
 		}
-		page++
+
+		if older {
+			// the next pages are older than minDate
+			break
+		}
 	}
 
-	spin.Done("✔️ fetched %d posts", len(posts))
+	return nil
+}
 
-	// Sort posts by time in descending order
-	slices.SortFunc(posts, func(a1, a2 Post) int {
-		return a2.Date.Compare(a1.Date.Time)
-	})
+// authoredBy returns true if the user is the author, or if there is no author to look for.
+func authoredBy(user github.User, author string) bool {
+	return author == "" || strings.EqualFold(user.GetLogin(), author)
+}
 
-	return posts, nil
+// fetchReactions fetches the reactions of the posts, with up to concurrency requests in flight.
+//
+// The reactions are returned in the order of the posts, whatever the order the requests complete.
+// The first error cancels the pending requests.
+func fetchReactions(ctx context.Context, client *gh.RESTClient, posts []Post, concurrency int) (Reactions, error) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	ctx, sp := startSpinner(ctx, "fetching reactions on posts")
+
+	type result struct {
+		index     int
+		reactions Reactions
+	}
+
+	indexes := make(chan int)
+	results := make(chan result)
+
+	go func() {
+		defer close(indexes)
+		for i := range posts {
+			select {
+			case indexes <- i:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for range max(concurrency, 1) {
+		wg.Go(func() {
+			for i := range indexes {
+				reactions, err := posts[i].FetchReactions(ctx, client)
+				if err != nil {
+					cancel(err)
+					return
+				}
+
+				select {
+				case results <- result{index: i, reactions: reactions}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		})
+	}
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	reactionsByPost := make([]Reactions, len(posts))
+	var checked, found int
+	for res := range results {
+		reactionsByPost[res.index] = res.reactions
+		checked++
+		found += len(res.reactions)
+		sp.Progress("checking reactions on posts %d/%d: %d reactions found", checked, len(posts), found)
+	}
+
+	if err := context.Cause(ctx); err != nil {
+		return nil, err
+	}
+
+	var allReactions Reactions
+	for _, reactions := range reactionsByPost {
+		allReactions.Append(reactions...)
+	}
+	sp.Done("✔️ fetched reactions on %d posts: %d reactions found", len(posts), len(allReactions))
+
+	return allReactions, nil
 }
 
 type Reactions []ReactionTo
@@ -227,13 +544,19 @@ func (r *Reactions) Append(reactions ...ReactionTo) {
 	*r = append(*r, reactions...)
 }
 
-func (r *Reactions) Clean() {
+func (r *Reactions) Clean(users userFilter) {
 	clean := slices.DeleteFunc(*r, func(r1 ReactionTo) bool {
-		// filter out bot reactions
-		return r1.Reaction.User.IsBot()
+		// filter out bot reactions, and the ones of the ignored users
+		if !users.match(r1.Reaction.User) {
+			return true
+		}
+
+		// filter out the reactions of the authors to their own posts
+		return !users.includeSelf && isSelfReaction(r1)
 	})
 
-	slices.SortFunc(clean, func(r1, r2 ReactionTo) int {
+	// stable sort, so reactions given at the same time keep the order of the posts
+	slices.SortStableFunc(clean, func(r1, r2 ReactionTo) int {
 		return r1.Reaction.CreatedAt.Compare(r2.Reaction.CreatedAt.Time)
 	})
 
@@ -241,18 +564,14 @@ func (r *Reactions) Clean() {
 }
 
 type ValueCount[T any] struct {
-	Value T
-	Count int
+	Value T   `json:"value"`
+	Count int `json:"count"`
 }
 
 type ValueCounts[T any] []ValueCount[T]
 
-func (v ValueCounts[T]) Top(nb int) ValueCounts[T] {
-	if nb <= 0 {
-		return nil
-	}
-
-	// Sort the values by count (descending)
+// Sort sorts the values by count (descending), values with the same count are sorted alphabetically.
+func (v ValueCounts[T]) Sort() ValueCounts[T] {
 	slices.SortFunc(v, func(a, b ValueCount[T]) int {
 		if a.Count == b.Count {
 			return cmp.Compare(fmt.Sprint(a.Value), fmt.Sprint(b.Value))
@@ -260,6 +579,15 @@ func (v ValueCounts[T]) Top(nb int) ValueCounts[T] {
 
 		return b.Count - a.Count
 	})
+	return v
+}
+
+func (v ValueCounts[T]) Top(nb int) ValueCounts[T] {
+	if nb <= 0 {
+		return nil
+	}
+
+	v.Sort()
 
 	// Return the top N values
 	if nb > len(v) {
@@ -286,6 +614,52 @@ func (v ValueCounts[T]) MaxSizeValue(f func(T) string) int {
 	return m
 }
 
+// ValueCountDiff is the change of the count and of the rank of a value between two [ValueCounts].
+//
+// The ranks start at 1, a zero rank means the value is missing.
+type ValueCountDiff[T any] struct {
+	Value         T   `json:"value"`
+	Count         int `json:"count"`
+	PreviousCount int `json:"previous_count"`
+	Delta         int `json:"delta"`
+	Rank          int `json:"rank"`
+	PreviousRank  int `json:"previous_rank"`
+}
+
+// Diff compares the values with the previous ones, the values are matched by their key.
+//
+// Both ValueCounts are sorted, the ranks are the positions of the values in them.
+// The values are returned in the order of v, followed by the ones found only in previous.
+func (v ValueCounts[T]) Diff(previous ValueCounts[T], key func(T) string) []ValueCountDiff[T] {
+	v.Sort()
+	previous.Sort()
+
+	previousRanks := make(map[string]int, len(previous))
+	for i, vc := range previous {
+		previousRanks[key(vc.Value)] = i + 1
+	}
+
+	diffs := make([]ValueCountDiff[T], 0, len(v))
+	for i, vc := range v {
+		diff := ValueCountDiff[T]{Value: vc.Value, Count: vc.Count, Rank: i + 1}
+		if rank, found := previousRanks[key(vc.Value)]; found {
+			diff.PreviousCount = previous[rank-1].Count
+			diff.PreviousRank = rank
+			delete(previousRanks, key(vc.Value))
+		}
+		diff.Delta = diff.Count - diff.PreviousCount
+		diffs = append(diffs, diff)
+	}
+
+	for i, vc := range previous {
+		if _, found := previousRanks[key(vc.Value)]; found {
+			diffs = append(diffs, ValueCountDiff[T]{Value: vc.Value, PreviousCount: vc.Count, Delta: -vc.Count, PreviousRank: i + 1})
+		}
+	}
+
+	return diffs
+}
+
 func (r Reactions) Users() ValueCounts[github.User] {
 	userCounts := make(map[string]ValueCount[github.User])
 
@@ -302,7 +676,7 @@ func (r Reactions) Users() ValueCounts[github.User] {
 		userCounts[key] = u
 	}
 
-	return slices.Collect(maps.Values(userCounts))
+	return slices.AppendSeq(make(ValueCounts[github.User], 0, len(userCounts)), maps.Values(userCounts))
 }
 
 func (r Reactions) Authors() ValueCounts[github.User] {
@@ -321,7 +695,7 @@ func (r Reactions) Authors() ValueCounts[github.User] {
 		userCounts[key] = u
 	}
 
-	return slices.Collect(maps.Values(userCounts))
+	return slices.AppendSeq(make(ValueCounts[github.User], 0, len(userCounts)), maps.Values(userCounts))
 }
 
 func (r Reactions) Posts() ValueCounts[Post] {
@@ -337,7 +711,7 @@ func (r Reactions) Posts() ValueCounts[Post] {
 		postCounts[key] = u
 	}
 
-	return slices.Collect(maps.Values(postCounts))
+	return slices.AppendSeq(make(ValueCounts[Post], 0, len(postCounts)), maps.Values(postCounts))
 }
 
 func (r Reactions) Reactions() ValueCounts[string] {
@@ -353,12 +727,12 @@ func (r Reactions) Reactions() ValueCounts[string] {
 		reactionCounts[key] = u
 	}
 
-	return slices.Collect(maps.Values(reactionCounts))
+	return slices.AppendSeq(make(ValueCounts[string], 0, len(reactionCounts)), maps.Values(reactionCounts))
 }
 
 type ReactionTo struct {
-	Reaction github.Reaction
-	Post     Post
+	Reaction github.Reaction `json:"reaction"`
+	Post     Post            `json:"post"`
 }
 
 func (r ReactionTo) String() string {
@@ -374,24 +748,71 @@ func (p Post) String() string {
 	sb := strings.Builder{}
 	sb.WriteString(fmt.Sprintf("Post message: %s\n", p.ContentPreview()))
 	sb.WriteString(fmt.Sprintf("Post type:    %s\n", p.Type))
+	sb.WriteString(fmt.Sprintf("Post repo:    %s\n", gh.RepositoryName(p.Repository)))
 	sb.WriteString(fmt.Sprintf("Post author:  %s\n", p.Author))
-	sb.WriteString(fmt.Sprintf("Post date:    %s\n", p.Date))
+	sb.WriteString(fmt.Sprintf("Post created: %s\n", p.CreatedAt))
+	sb.WriteString(fmt.Sprintf("Post updated: %s\n", p.UpdatedAt))
 	sb.WriteString(fmt.Sprintf("Post link:    %s\n", p.Link))
 	return sb.String()
 }
 
+// reactionsURI returns the URI listing the reactions of the post, see reactionsEndpoints.
+func (p Post) reactionsURI() (string, error) {
+	endpoint, found := reactionsEndpoints[p.Type]
+	if !found {
+		return "", fmt.Errorf("no endpoint to fetch the reactions of %s %s", p.Type, p.Link)
+	}
+	return fmt.Sprintf("repos/%s/%s/%s?per_page=100", p.Repository.Owner, p.Repository.Name, fmt.Sprintf(endpoint, p.ID)), nil
+}
+
 func parseCLIOptions() (cliOptions, error) {
 	var opts cliOptions
 	fl := flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
 
 	fl.StringVar(&opts.author, "author", "", "Limit to messages authored by this GitHub username")
+	fl.Var(&opts.repos, "repo", `Analyze this repository "owner/name", can be repeated (default: the current repository)`)
+	fl.BoolVar(&opts.global, "global", false, "Analyze the messages of -author (default: the authenticated user) on all the repositories, using the search API")
+	fl.StringVar(&opts.reposFile, "repos-file", "", "Analyze the repositories listed in this file, one per line")
+	fl.StringVar(&opts.org.name, "org", "", "Analyze the repositories of this organization, except the archived ones")
+	fl.BoolVar(&opts.org.excludeForks, "exclude-forks", false, "Ignore the forks of the organization with -org")
+	fl.Var(&opts.org.include, "include-repo", `Analyze only the repositories of the organization matching this glob (e.g., "gh-*"), can be repeated`)
+	fl.Var(&opts.org.exclude, "exclude-repo", "Ignore the repositories of the organization matching this glob, can be repeated")
 	fl.IntVar(&opts.limit, "limit", 50, "Maximum number of messages to fetch")
+	fl.Var(&opts.reactions.include, "reaction", `Analyze only this reaction, by name (e.g., "+1", "heart", "rocket") or emoji, can be repeated`)
+	fl.Var(&opts.reactions.exclude, "exclude-reaction", "Ignore this reaction, by name or emoji, can be repeated")
+	fl.Var(&opts.users.ignore, "ignore-user", `Ignore the reactions of this user, or of the users matching this glob (e.g., "release-*"), can be repeated`)
+	fl.BoolVar(&opts.users.includeBots, "include-bots", false, "Analyze the reactions of the bots too")
+	fl.BoolVar(&opts.users.includeSelf, "include-self-reactions", false, "Analyze the reactions of the authors to their own messages too")
+
+	opts.backend = backendREST
+	fl.Var(&opts.backend, "backend", fmt.Sprintf("API used to fetch posts and reactions, one of %v", backends))
+	fl.BoolVar(&opts.noCache, "no-cache", false, "Do not use the cache of the GitHub API responses")
+	fl.IntVar(&opts.concurrency, "concurrency", 4, "Number of posts whose reactions are fetched in parallel")
+	fl.IntVar(&opts.top, "top", 5, "Number of entries displayed in the top lists")
+
+	opts.format = outputFormatText
+	fl.Var(&opts.format, "format", fmt.Sprintf("Output format, one of %v", outputFormats))
+	fl.Var(&opts.timeline, "timeline", fmt.Sprintf("Show the reactions per time unit, one of %v", timelineUnits))
+	fl.Var(&opts.compare, "compare", `Compare with the "previous" period of equal length, or with this period (e.g., "Q4-2023")`)
+	fl.BoolVar(&opts.heatmap, "heatmap", false, "Show the reactions per weekday and hour")
+	timezone := fl.String("timezone", "Local", `Time zone of the timeline and the heatmap (e.g., "UTC", "Europe/Paris")`)
+	fl.StringVar(&opts.jq, "jq", "", "Filter the JSON report using a jq expression")
+	fl.StringVar(&opts.template, "template", "", "Format the JSON report using a Go template, see 'gh help formatting'")
 
 	defaultSinceDaysAgo := 90
-	fl.Var(&opts.since, "since", fmt.Sprintf(`Fetch messages since this date (e.g., "2023-01-02", "2h", "15m", "3d" ...) (default "%dd")`, defaultSinceDaysAgo))
+	defaultPostsSinceDaysBefore := 90
+	fl.Var(&opts.period, "since", fmt.Sprintf(`Fetch messages since this date (e.g., "2023-01-02", "2h", "15m", "3d" ...), or in this period (e.g., "2024-01-01..2024-03-31", "Q1-2024", "last-month") (default "%dd")`, defaultSinceDaysAgo))
+	fl.Var(opts.period.UntilValue(), "until", `Fetch messages until this date, included (e.g., "2024-03-31")`)
+	opts.dateField = dateFieldUpdated
+	fl.Var(&opts.dateField, "date-field", fmt.Sprintf("Date used to select and sort the messages, one of %v", dateFields))
+	fl.Var(&opts.postsSince, "posts-since", fmt.Sprintf("Fetch messages since this date, to find the older messages that got reactions since -since (default: %d days before -since, or the -since date with -date-field created)", defaultPostsSinceDaysBefore))
+	fl.BoolVar(&opts.allReactions, "all-reactions", false, "Analyze all the reactions of the messages, not only the ones given in the period")
 
 	fl.Usage = func() {
 		// add a simple --help flag
+		fmt.Print("Available Commands:\n")
+		fmt.Print("  cache clear\n")
+		fmt.Print("        Remove the cached GitHub API responses\n\n")
 		fmt.Print("Available Flags:\n")
 		fl.PrintDefaults()
 	}
@@ -400,168 +821,583 @@ func parseCLIOptions() (cliOptions, error) {
 		return opts, err
 	}
 
-	if opts.since.IsZero() {
-		opts.since = timeago.NewRelativeDate(time.Now().AddDate(0, 0, -defaultSinceDaysAgo))
+	if opts.reposFile != "" {
+		if err := opts.repos.ReadFile(opts.reposFile); err != nil {
+			return opts, err
+		}
+	}
+
+	for i, login := range opts.users.ignore {
+		opts.users.ignore[i] = strings.ToLower(login)
+	}
+	if err := opts.users.ReadConfig(); err != nil {
+		return opts, err
+	}
+	if err := opts.users.validate(); err != nil {
+		return opts, err
+	}
+
+	for _, pattern := range slices.Concat(opts.org.include, opts.org.exclude) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return opts, fmt.Errorf("invalid repository glob %q: %w", pattern, err)
+		}
+	}
+
+	if opts.global && (len(opts.repos) > 0 || opts.org.name != "") {
+		return opts, errors.New("the -global flag cannot be used with -repo, -repos-file or -org")
+	}
+	if opts.global && opts.backend != backendREST {
+		return opts, errors.New("the -global flag is only available with the rest backend")
+	}
+
+	if opts.allReactions && opts.compare.enabled() {
+		return opts, errors.New("the -all-reactions flag cannot be used with -compare, the reactions are split by the date they were given")
+	}
+
+	if opts.top < 1 {
+		return opts, errors.New("the -top flag must be at least 1")
+	}
+
+	if opts.jq != "" && opts.template != "" {
+		return opts, errors.New("the -jq and -template flags are mutually exclusive")
+	}
+	if (opts.jq != "" || opts.template != "") && opts.format != outputFormatText && opts.format != outputFormatJSON {
+		return opts, fmt.Errorf("the -jq and -template flags cannot be used with the %s format", opts.format)
+	}
+
+	opts.location, err = time.LoadLocation(*timezone)
+	if err != nil {
+		return opts, fmt.Errorf("invalid time zone: %w", err)
+	}
+
+	if opts.period.Since.IsZero() {
+		// the default period ends with -until, when set
+		end := time.Now()
+		if !opts.period.Until.IsZero() {
+			end = opts.period.Until.Time
+		}
+		opts.period.Since = timeago.NewRelativeDate(end.AddDate(0, 0, -defaultSinceDaysAgo))
+	}
+	opts.period.Since.Time = opts.period.Since.Time.Truncate(time.Hour).UTC()
+	if !opts.period.Until.IsZero() && !opts.period.Until.After(opts.period.Since.Time) {
+		return opts, fmt.Errorf("the end of the period %s is before its start", opts.period)
+	}
+
+	if opts.postsSince.IsZero() {
+		opts.postsSince = opts.period.Since
+		if opts.dateField == dateFieldUpdated {
+			// a reaction doesn't update a message, the older messages may have got reactions in the period
+			opts.postsSince = timeago.NewRelativeDate(opts.period.Since.AddDate(0, 0, -defaultPostsSinceDaysBefore))
+		}
+	}
+	opts.postsSince.Time = opts.postsSince.Time.Truncate(time.Hour).UTC()
+	if opts.postsSince.After(opts.period.Since.Time) {
+		return opts, errors.New("the -posts-since date cannot be after the -since one")
 	}
-	opts.since.Time = opts.since.Time.Truncate(time.Hour).UTC()
 
 	return opts, nil
 }
 
-func execute(ctx context.Context) error {
-	client, err := gh.DefaultRESTClient()
+// executeCache runs the "cache" command.
+func executeCache(args []string) error {
+	if len(args) != 1 || args[0] != "clear" {
+		return errors.New(`unknown cache command, expected "cache clear"`)
+	}
+
+	dir, err := gh.CacheDir()
 	if err != nil {
 		return err
 	}
 
-	repo, err := gh.CurrentRepository()
-	if err != nil {
+	if err := os.RemoveAll(dir); err != nil {
 		return err
 	}
 
+	fmt.Println("✔️ cache cleared:", dir)
+	return nil
+}
+
+// listRepositories returns the repositories to analyze: the ones given with -repo and -repos-file,
+// the ones of the organization given with -org, or the current repository.
+func listRepositories(ctx context.Context, client *gh.RESTClient, opts cliOptions) ([]gh.Repository, error) {
+	repos := slices.Clone(opts.repos)
+	if opts.org.name != "" {
+		orgRepos, err := fetchOrgRepositories(ctx, client, opts.org)
+		if err != nil {
+			return nil, err
+		}
+		for _, repo := range orgRepos {
+			if !slices.Contains(repos, repo) {
+				repos = append(repos, repo)
+			}
+		}
+	}
+	if len(repos) == 0 {
+		repo, err := gh.CurrentRepository()
+		if err != nil {
+			return nil, err
+		}
+		repos = append(repos, repo)
+	}
+	return repos, nil
+}
+
+func execute(ctx context.Context) error {
+	if len(os.Args) > 1 && os.Args[1] == "cache" {
+		return executeCache(os.Args[2:])
+	}
+
 	opts, err := parseCLIOptions()
 	if err != nil {
 		return err
 	}
 
-	since := opts.since
+	var transport http.RoundTripper = gh.NewRateLimitTransport(nil)
+	if !opts.noCache {
+		cacheDir, err := gh.CacheDir()
+		if err != nil {
+			return err
+		}
+		cache := gh.NewCacheTransport(cacheDir, transport)
+		// the cache is a best effort, a failure to prune it must not fail the analysis
+		_ = cache.Prune(gh.CacheMaxAge)
+		transport = cache
+	}
 
-	allPosts, err := fetchPosts(ctx, client, repo, since)
+	client, err := gh.NewRESTClient(gh.ClientOptions{Transport: transport})
 	if err != nil {
 		return err
 	}
 
-	if len(allPosts) == 0 {
-		fmt.Println("\nNo posts found since ", since.String())
-		return nil
+	graphqlClient, err := gh.NewGraphQLClient(gh.ClientOptions{Transport: transport})
+	if err != nil {
+		return err
+	}
+
+	// the posts of the compared period are fetched along with the ones of the period
+	since := opts.postsSince
+	var comparedPeriod timeago.DateRange
+	if opts.compare.enabled() {
+		comparedPeriod = opts.compare.resolve(opts.period)
+		if comparedSince := comparedPostsSince(opts, comparedPeriod); comparedSince.Before(since.Time) {
+			since = comparedSince
+		}
 	}
 
-	posts := allPosts
-	if opts.author != "" {
-		// Keep only posts authored by the current user
-		posts = slices.DeleteFunc(posts, func(a Post) bool {
-			if a.Author.Login == nil {
-				return false
+	var repos []gh.Repository
+	var allPosts []Post
+	if opts.global {
+		if opts.author == "" {
+			opts.author, err = fetchAuthenticatedUser(ctx, client)
+			if err != nil {
+				return err
 			}
+		}
 
-			return !strings.EqualFold(*a.Author.Login, opts.author)
-		})
-		fmt.Printf("Limited analysis to %d %s posts\n", len(posts), opts.author)
-	}
+		allPosts, err = fetchUserPosts(ctx, client, opts.author, since)
+		if err != nil {
+			return err
+		}
+
+		for _, post := range allPosts {
+			if !slices.Contains(repos, post.Repository) {
+				repos = append(repos, post.Repository)
+			}
+		}
+	} else {
+		repos, err = listRepositories(ctx, client, opts)
+		if err != nil {
+			return err
+		}
 
-	if opts.limit > 0 && len(posts) > opts.limit {
-		posts = posts[:opts.limit]
+		for _, repo := range repos {
+			var repoPosts []Post
+			switch opts.backend {
+			case backendGraphQL:
+				repoPosts, err = fetchPostsGraphQL(ctx, graphqlClient, client, repo, since, opts.author)
+				if err != nil {
+					return err
+				}
+			default:
+				repoPosts, err = fetchPosts(ctx, client, graphqlClient, repo, since, opts.author)
+				if err != nil {
+					return err
+				}
+			}
+			allPosts = append(allPosts, repoPosts...)
+		}
+	}
 
-		lastPost := posts[len(posts)-1]
-		since = timeago.NewRelativeDate(lastPost.Date.Time)
-		fmt.Printf("⚠️ Limited analysis to latest %d posts since %s\n", len(posts), since.String())
+	for i := range allPosts {
+		allPosts[i].Date = opts.dateField.date(allPosts[i])
 	}
 
-	sp := spinner.New(os.Stdout)
-	sp.Start(ctx, "fetching %d reactions on posts")
+	// Sort posts of all the repositories by time in descending order
+	slices.SortStableFunc(allPosts, func(a1, a2 Post) int {
+		return a2.Date.Compare(a1.Date.Time)
+	})
 
-	var allReactions Reactions
-	for i, post := range posts {
-		sp.Progress("checking reactions on posts %d/%d: %d reactions found", i, len(posts), len(allReactions))
-		reactions, err := post.FetchReactions(ctx, client, repo)
-		if err != nil {
-			return err
+	// the posts are already limited to the ones of opts.author
+	posts := selectPeriodPosts(allPosts, opts.dateField, opts.limit, opts.period, opts.postsSince)
+	posts.warn()
+	analyzedPosts := slices.Clone(posts.analyzed)
+
+	var comparedPosts periodPosts
+	if opts.compare.enabled() {
+		fmt.Fprintf(os.Stderr, "Comparing with %s\n", comparedPeriod)
+		comparedPosts = selectPeriodPosts(allPosts, opts.dateField, opts.limit, comparedPeriod, comparedPostsSince(opts, comparedPeriod))
+		comparedPosts.warn()
+
+		// the posts analyzed in both periods are fetched once
+		links := make(map[string]bool, len(analyzedPosts))
+		for _, p := range analyzedPosts {
+			links[p.Link] = true
 		}
-		allReactions.Append(reactions...)
+		analyzedPosts = append(analyzedPosts, filter(comparedPosts.analyzed, func(p Post) bool {
+			return !links[p.Link]
+		})...)
 	}
-	sp.Done("✔️ fetched reactions on %d posts: %d reactions found", len(posts), len(allReactions))
 
-	allReactions.Clean()
+	// the reactions of both periods are fetched at once, then split by the date they were given
+	reactions, err := fetchReactions(ctx, client, analyzedPosts, opts.concurrency)
+	if err != nil {
+		return err
+	}
+	reactions.Clean(opts.users)
 
-	fmt.Println("Stats since", since)
-	fmt.Println(len(allPosts), "messages on repository")
-	fmt.Println(len(posts), "analyzed messages")
-	postsWithReactions := allReactions.Posts()
-	fmt.Println(len(postsWithReactions), "messages with reactions")
-	fmt.Println()
+	report := analyze(opts, repos, posts, reactions)
 
-	if len(postsWithReactions) == 0 {
-		return nil
+	if opts.compare.enabled() {
+		comparison := newComparison(report, analyze(opts, repos, comparedPosts, reactions))
+		report.Comparison = &comparison
 	}
 
-	var reactionDetails []string
-	topReactions := allReactions.Reactions()
-	for _, reaction := range topReactions {
-		reactionDetails = append(reactionDetails, fmt.Sprintf("%d: %s", reaction.Count, reaction.Value))
+	return opts.format.Write(os.Stdout, report, renderOptions{
+		top:      opts.top,
+		jq:       opts.jq,
+		template: opts.template,
+	})
+}
+
+// comparedPostsSince returns the start of the posts of the compared period,
+// as much before the start of the compared period as -posts-since is before -since.
+func comparedPostsSince(opts cliOptions, compared timeago.DateRange) timeago.RelativeDate {
+	return timeago.NewRelativeDate(compared.Since.Add(-opts.period.Since.Sub(opts.postsSince.Time)))
+}
+
+// periodPosts holds the posts of an analyzed period.
+type periodPosts struct {
+	// period is the period the reactions are analyzed in, it starts at postsSince when the limit is reached.
+	period timeago.DateRange
+	// postsSince is the start of the posts, it's the date of the oldest analyzed post when the limit is reached.
+	postsSince timeago.RelativeDate
+	// all are the posts of the period.
+	all []Post
+	// analyzed are the latest posts, within the limit.
+	analyzed []Post
+	// limited is set when the limit is reached.
+	limited bool
+}
+
+// selectPeriodPosts selects the posts of the period since postsSince, and the latest limit of them to analyze.
+//
+// The posts are sorted by date (descending). When the limit is reached, the period starts at the date of
+// the oldest analyzed post, but not after the end of the period: the posts updated after the end of
+// the period may have got reactions in it, the reactions of the analyzed posts are not ignored then.
+func selectPeriodPosts(posts []Post, field dateField, limit int, period timeago.DateRange, postsSince timeago.RelativeDate) periodPosts {
+	// a reaction doesn't update a post, the older posts may have got reactions in period
+	all := selectPosts(posts, field, timeago.DateRange{Since: postsSince, Until: period.Until})
+	selected := periodPosts{
+		period:     period,
+		postsSince: postsSince,
+		all:        all,
+		analyzed:   all,
+	}
+	if limit <= 0 || len(all) <= limit {
+		return selected
 	}
-	fmt.Printf("Total reactions: %d (%s)\n\n", len(allReactions), strings.Join(reactionDetails, " "))
 
-	topPosts := postsWithReactions.Top(5)
-	if len(postsWithReactions) > len(topPosts) {
-		fmt.Println("Messages with most reactions:")
-	} else {
-		fmt.Println("Messages with reactions:")
+	selected.analyzed = all[:limit]
+	selected.limited = true
+
+	since := timeago.NewRelativeDate(selected.analyzed[limit-1].Date.Time)
+	if !period.Until.IsZero() && since.After(period.Until.Time) {
+		since = period.Until
+	}
+	if since.After(selected.postsSince.Time) {
+		selected.postsSince = since
+		if since.After(selected.period.Since.Time) {
+			selected.period.Since = since
+		}
 	}
 
-	for _, post := range topPosts {
-		fmt.Printf("Reactions:    %d\n", post.Count)
-		fmt.Print(post.Value.String())
-		fmt.Println()
+	return selected
+}
+
+// warn warns on stderr when the limit is reached.
+func (p periodPosts) warn() {
+	if !p.limited {
+		return
 	}
-	fmt.Println()
 
-	authors := allReactions.Authors()
-	topAuthors := authors.Top(5)
-	if len(authors) > len(topAuthors) {
-		fmt.Println("Total users who got reactions:", len(authors))
-		fmt.Println("\nTop users who got reactions:")
-	} else {
-		fmt.Println("Users who got reactions:")
+	fmt.Fprintf(os.Stderr, "⚠️ Limited analysis to latest %d posts since %s\n", len(p.analyzed), p.postsSince.String())
+	if !p.period.Until.IsZero() && !p.period.Until.After(p.period.Since.Time) {
+		fmt.Fprintf(os.Stderr, "⚠️ The latest %d posts were updated after the end of the period, increase -limit to analyze the reactions given in it\n", len(p.analyzed))
 	}
+}
+
+// analyze builds the report of the reactions given in the period, to the analyzed posts.
+//
+// The reactions are the cleaned reactions of the posts of all the analyzed periods, sorted by date:
+// the reactions are fetched once, and split by period.
+func analyze(opts cliOptions, repos []gh.Repository, posts periodPosts, reactions Reactions) Report {
+	links := make(map[string]bool, len(posts.analyzed))
+	for _, p := range posts.analyzed {
+		links[p.Link] = true
+	}
+	reactions = filter(reactions, func(r ReactionTo) bool {
+		return links[r.Post.Link]
+	})
+	reactions = selectReactions(reactions, posts.period, opts.allReactions, opts.reactions)
 
-	maxSizeCount := topAuthors.MaxSizeCount()
-	maxSizeLogin := topAuthors.MaxSizeValue(func(u github.User) string {
-		if u.Login == nil {
-			return ""
+	report := newReport(repos, posts.period, posts.postsSince, opts.author, posts.all, posts.analyzed, reactions)
+	report.DateField = opts.dateField
+	if opts.timeline != "" {
+		timeline := newTimeline(reactions, opts.timeline, opts.location, posts.period)
+		report.Timeline = &timeline
+	}
+	if opts.heatmap {
+		heatmap := newHeatmap(reactions, opts.location, posts.period)
+		report.Heatmap = &heatmap
+	}
+
+	return report
+}
+
+// selectPosts returns the posts of the period, in a new slice.
+//
+// The posts updated after the end of the period are kept, as they may have got reactions in the period,
+// but not the ones created after it. The end bounds the creation date, the start bounds the date field:
+// the update date is always after it, as the API returns the posts updated since then.
+func selectPosts(posts []Post, field dateField, period timeago.DateRange) []Post {
+	return filter(posts, func(p Post) bool {
+		if field == dateFieldCreated {
+			return period.Contains(p.CreatedAt.Time)
 		}
-		return *u.Login
+		return !p.UpdatedAt.Before(period.Since.Time) && (period.Until.IsZero() || p.CreatedAt.Before(period.Until.Time))
+	})
+}
+
+// selectReactions returns the reactions given in the period and matching the filter, in a new slice.
+//
+// The reactions given out of the period are kept when all is set.
+func selectReactions(reactions Reactions, period timeago.DateRange, all bool, reactionsFilter reactionFilter) Reactions {
+	return filter(reactions, func(r ReactionTo) bool {
+		return (all || period.Contains(r.Reaction.CreatedAt.Time)) && reactionsFilter.match(r.Reaction.Content)
 	})
+}
+
+type cliOptions struct {
+	author string
+	limit  int
 
-	for _, user := range topAuthors {
-		fmt.Printf("%*s %-*s %s\n", maxSizeCount, strconv.Itoa(user.Count), maxSizeLogin, user.Value, user.Value.GitHubURL())
+	repos     repositories
+	reposFile string
+	org       orgOptions
+	global    bool
+
+	period       timeago.DateRange
+	dateField    dateField
+	postsSince   timeago.RelativeDate
+	allReactions bool
+	reactions    reactionFilter
+	users        userFilter
+	format       outputFormat
+	top          int
+	timeline     timelineUnit
+	heatmap      bool
+	compare      comparePeriod
+	location     *time.Location
+
+	backend     backend
+	concurrency int
+	noCache     bool
+
+	jq       string
+	template string
+}
+
+// repositories is a list of repositories set with a repeatable flag.
+type repositories []gh.Repository
+
+// String returns the names of the repositories.
+//
+// It satisfies the [flag.Value] and [fmt.Stringer] interface.
+func (r repositories) String() string {
+	names := make([]string, 0, len(r))
+	for _, repo := range r {
+		names = append(names, gh.RepositoryName(repo))
 	}
-	fmt.Println()
+	return strings.Join(names, ", ")
+}
 
-	users := allReactions.Users()
-	topUsers := users.Top(5)
-	if len(users) > len(topUsers) {
-		fmt.Println("Total users who reacted:", len(users))
-		fmt.Println("Top users who reacted:")
-	} else {
-		fmt.Println("Users who reacted:", len(users))
+// Set adds a repository from its name.
+//
+// The repositories of another host than the default one are rejected, the clients only call the default host.
+//
+// It satisfies the [flag.Value] interface.
+func (r *repositories) Set(value string) error {
+	repo, err := gh.ParseRepository(value)
+	if err != nil {
+		return err
+	}
+	if host := gh.DefaultHost(); !strings.EqualFold(repo.Host, host) {
+		return fmt.Errorf("repository %q is not on %s, set GH_HOST to analyze the repositories of %s", value, host, repo.Host)
+	}
+
+	if !slices.Contains(*r, repo) {
+		*r = append(*r, repo)
+	}
+	return nil
+}
+
+// ReadFile adds the repositories listed in a file, one per line.
+//
+// The empty lines, and the lines starting with # are ignored.
+func (r *repositories) ReadFile(name string) error {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return err
 	}
 
-	maxSizeCount = topUsers.MaxSizeCount()
-	maxSizeLogin = topUsers.MaxSizeValue(func(u github.User) string {
-		if u.Login == nil {
-			return ""
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
 		}
-		return *u.Login
-	})
 
-	for _, user := range topUsers {
-		fmt.Printf("%*s %-*s %s\n", maxSizeCount, strconv.Itoa(user.Count), maxSizeLogin, user.Value, user.Value.GitHubURL())
+		if err := r.Set(line); err != nil {
+			return fmt.Errorf("%s:%d: %w", name, i+1, err)
+		}
 	}
-	fmt.Println()
+	return nil
+}
+
+// reactionList is a list of reaction contents set with a repeatable flag.
+type reactionList []string
 
-	fmt.Println("Last reactions:")
-	for _, reaction := range allReactions {
-		fmt.Print(reaction.String())
-		fmt.Println()
+// String returns the contents of the reactions.
+//
+// It satisfies the [flag.Value] and [fmt.Stringer] interface.
+func (r reactionList) String() string {
+	return strings.Join(r, ", ")
+}
+
+// Set adds a reaction from its name or its emoji.
+//
+// It satisfies the [flag.Value] interface.
+func (r *reactionList) Set(value string) error {
+	content, err := github.ParseReactionContent(value)
+	if err != nil {
+		return err
 	}
 
+	if !slices.Contains(*r, content) {
+		*r = append(*r, content)
+	}
 	return nil
 }
 
-type cliOptions struct {
-	author string
-	limit  int
-	since  timeago.RelativeDate
+// reactionFilter selects the reactions to analyze by their content.
+type reactionFilter struct {
+	include reactionList
+	exclude reactionList
+}
+
+// match reports whether the reaction content is selected by the include and exclude lists.
+func (f reactionFilter) match(content string) bool {
+	if len(f.include) > 0 && !slices.Contains(f.include, content) {
+		return false
+	}
+	return !slices.Contains(f.exclude, content)
+}
+
+// dateField is the date of the posts used to select and sort them.
+type dateField string
+
+const (
+	// dateFieldCreated selects the posts by their creation date.
+	dateFieldCreated dateField = "created"
+	// dateFieldUpdated selects the posts by their last update date.
+	dateFieldUpdated dateField = "updated"
+)
+
+var dateFields = []dateField{
+	dateFieldCreated,
+	dateFieldUpdated,
+}
+
+// String returns the name of the date field.
+//
+// It satisfies the [flag.Value] and [fmt.Stringer] interface.
+func (f dateField) String() string {
+	return string(f)
+}
+
+// Set sets the date field from its name.
+//
+// It satisfies the [flag.Value] interface.
+func (f *dateField) Set(value string) error {
+	for _, field := range dateFields {
+		if strings.EqualFold(value, string(field)) {
+			*f = field
+			return nil
+		}
+	}
+	return fmt.Errorf("unsupported date field %q, expected one of %v", value, dateFields)
+}
+
+// date returns the date of the post for the field.
+func (f dateField) date(p Post) github.Time {
+	if f == dateFieldCreated {
+		return p.CreatedAt
+	}
+	return p.UpdatedAt
+}
+
+// backend is the GitHub API used to fetch the posts and their reactions.
+type backend string
+
+const (
+	// backendREST fetches the posts, then the reactions of each post.
+	backendREST backend = "rest"
+	// backendGraphQL fetches the posts with their reactions in bulk.
+	backendGraphQL backend = "graphql"
+)
+
+var backends = []backend{
+	backendREST,
+	backendGraphQL,
+}
+
+// String returns the name of the backend.
+//
+// It satisfies the [flag.Value] and [fmt.Stringer] interface.
+func (b backend) String() string {
+	return string(b)
+}
+
+// Set sets the backend from its name.
+//
+// It satisfies the [flag.Value] interface.
+func (b *backend) Set(value string) error {
+	for _, backend := range backends {
+		if strings.EqualFold(value, string(backend)) {
+			*b = backend
+			return nil
+		}
+	}
+	return fmt.Errorf("unsupported backend %q, expected one of %v", value, backends)
 }
 
 type exitCode = int
@@ -0,0 +1,566 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ccoVeille/gh-reaction/internal/gh"
+	"github.com/ccoVeille/gh-reaction/internal/github"
+	"github.com/ccoVeille/gh-reaction/internal/timeago"
+)
+
+func TestReactionListSet(t *testing.T) {
+	var list reactionList
+	for _, value := range []string{"+1", "👍", "heart", "❤️", "Heart"} {
+		if err := list.Set(value); err != nil {
+			t.Fatalf("unexpected error for %q: %v", value, err)
+		}
+	}
+
+	if expected := (reactionList{"+1", "heart"}); !slices.Equal(list, expected) {
+		t.Errorf("expected %v, got %v", expected, list)
+	}
+
+	if err := list.Set("tada"); err == nil {
+		t.Error("expected an error for an unknown reaction")
+	}
+}
+
+func TestReactionFilterMatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		filter   reactionFilter
+		expected []string
+	}{
+		{
+			name:     "No filter",
+			filter:   reactionFilter{},
+			expected: []string{"+1", "-1", "heart", "rocket"},
+		},
+		{
+			name:     "Include",
+			filter:   reactionFilter{include: reactionList{"+1", "heart"}},
+			expected: []string{"+1", "heart"},
+		},
+		{
+			name:     "Exclude",
+			filter:   reactionFilter{exclude: reactionList{"-1"}},
+			expected: []string{"+1", "heart", "rocket"},
+		},
+		{
+			name:     "Include and exclude",
+			filter:   reactionFilter{include: reactionList{"+1", "heart"}, exclude: reactionList{"heart", "rocket"}},
+			expected: []string{"+1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, content := range []string{"+1", "-1", "heart", "rocket"} {
+				if tt.filter.match(content) {
+					got = append(got, content)
+				}
+			}
+			if !slices.Equal(got, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestReactionsClean(t *testing.T) {
+	reaction := func(reactor, author string, minutes int) ReactionTo {
+		var r ReactionTo
+		r.Reaction.User = newUser(reactor, "User")
+		r.Reaction.Content = "+1"
+		r.Reaction.CreatedAt.Time = time.Date(2024, 3, 1, 0, minutes, 0, 0, time.UTC)
+		if author != "" {
+			r.Post.Author = newUser(author, "User")
+		}
+		r.Post.Link = reactor + "/" + author
+		return r
+	}
+
+	reactions := Reactions{
+		reaction("octocat", "octocat", 5),       // self-reaction
+		reaction("OctoCat", "octocat", 4),       // self-reaction, whatever the case of the login
+		reaction("hubot", "octocat", 3),         // kept
+		reaction("dependabot[bot]", "hubot", 2), // bot
+		reaction("octocat", "", 1),              // no author, kept
+	}
+
+	tests := []struct {
+		name     string
+		users    userFilter
+		expected []string
+	}{
+		{
+			name:     "Default",
+			users:    userFilter{},
+			expected: []string{"octocat/", "hubot/octocat"},
+		},
+		{
+			name:     "Self-reactions included",
+			users:    userFilter{includeSelf: true},
+			expected: []string{"octocat/", "hubot/octocat", "OctoCat/octocat", "octocat/octocat"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleaned := slices.Clone(reactions)
+			cleaned.Clean(tt.users)
+
+			var got []string
+			for _, r := range cleaned {
+				got = append(got, r.Post.Link)
+			}
+			// the reactions are sorted by date
+			if !slices.Equal(got, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestSelectPostsAndReactions(t *testing.T) {
+	period := timeago.DateRange{
+		Since: timeago.NewRelativeDate(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)),
+		Until: timeago.NewRelativeDate(time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)),
+	}
+	date := func(year int, month time.Month, day int) github.Time {
+		return github.Time{Time: time.Date(year, month, day, 0, 0, 0, 0, time.UTC)}
+	}
+
+	posts := []Post{
+		{Link: "created and updated in the period", CreatedAt: date(2024, 1, 1), UpdatedAt: date(2024, 3, 31)},
+		{Link: "updated after the period", CreatedAt: date(2024, 2, 1), UpdatedAt: date(2024, 6, 1)},
+		{Link: "created before the period", CreatedAt: date(2023, 6, 1), UpdatedAt: date(2024, 2, 1)},
+		{Link: "created after the period", CreatedAt: date(2024, 4, 1), UpdatedAt: date(2024, 4, 2)},
+		{Link: "updated before the period", CreatedAt: date(2023, 6, 1), UpdatedAt: date(2023, 12, 31)},
+	}
+
+	tests := []struct {
+		field    dateField
+		expected []string
+	}{
+		{
+			field:    dateFieldUpdated,
+			expected: []string{"created and updated in the period", "updated after the period", "created before the period"},
+		},
+		{
+			field:    dateFieldCreated,
+			expected: []string{"created and updated in the period", "updated after the period"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.field.String(), func(t *testing.T) {
+			var got []string
+			for _, p := range selectPosts(posts, tt.field, period) {
+				got = append(got, p.Link)
+			}
+			if !slices.Equal(got, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+
+	var reactions Reactions
+	for _, reactedAt := range []github.Time{date(2023, 12, 31), date(2024, 1, 1), date(2024, 3, 31), date(2024, 4, 1)} {
+		for _, content := range []string{"+1", "heart"} {
+			var r ReactionTo
+			r.Post = posts[1]
+			r.Reaction.Content = content
+			r.Reaction.CreatedAt = reactedAt
+			reactions = append(reactions, r)
+		}
+	}
+
+	// the post updated after the period keeps the reactions given in the period
+	selected := selectReactions(reactions, period, false, reactionFilter{include: reactionList{"heart"}})
+	if len(selected) != 2 || !selected[0].Reaction.CreatedAt.Equal(date(2024, 1, 1).Time) || !selected[1].Reaction.CreatedAt.Equal(date(2024, 3, 31).Time) {
+		t.Errorf("expected the 2 hearts given in the period, got %v", selected)
+	}
+
+	if selected := selectReactions(reactions, period, true, reactionFilter{}); len(selected) != len(reactions) {
+		t.Errorf("expected all the %d reactions, got %d", len(reactions), len(selected))
+	}
+}
+
+func TestAnalyzeLimit(t *testing.T) {
+	date := func(year int, month time.Month, day int) github.Time {
+		return github.Time{Time: time.Date(year, month, day, 0, 0, 0, 0, time.UTC)}
+	}
+	period := timeago.DateRange{
+		Since: timeago.NewRelativeDate(date(2024, 1, 1).Time),
+		Until: timeago.NewRelativeDate(date(2024, 4, 1).Time),
+	}
+
+	// sorted by update date, as done by execute
+	posts := []Post{
+		{Link: "created after the period", CreatedAt: date(2024, 5, 1), UpdatedAt: date(2024, 6, 20)},
+		{Link: "updated after the period", CreatedAt: date(2024, 2, 1), UpdatedAt: date(2024, 6, 10)},
+		{Link: "updated in the period", CreatedAt: date(2024, 1, 10), UpdatedAt: date(2024, 3, 1)},
+		{Link: "updated before the period", CreatedAt: date(2023, 11, 1), UpdatedAt: date(2023, 12, 1)},
+	}
+	for i := range posts {
+		posts[i].Date = dateFieldUpdated.date(posts[i])
+	}
+
+	var reactions Reactions
+	for _, reactedAt := range []github.Time{date(2024, 2, 15), date(2024, 3, 15)} {
+		for _, p := range posts {
+			var r ReactionTo
+			r.Post = p
+			r.Reaction.Content = "+1"
+			r.Reaction.CreatedAt = reactedAt
+			reactions = append(reactions, r)
+		}
+	}
+
+	tests := []struct {
+		name              string
+		limit             int
+		expectedSince     time.Time
+		expectedPosts     int
+		expectedAnalyzed  int
+		expectedReactions int
+	}{
+		{"No limit", 0, date(2024, 1, 1).Time, 3, 3, 6},
+		{"Limit not reached", 3, date(2024, 1, 1).Time, 3, 3, 6},
+		{"Limit reached in the period", 2, date(2024, 3, 1).Time, 3, 2, 2},
+		{"Limit reached after the period", 1, date(2024, 4, 1).Time, 3, 1, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := cliOptions{dateField: dateFieldUpdated, limit: tt.limit}
+			selected := selectPeriodPosts(posts, opts.dateField, opts.limit, period, timeago.NewRelativeDate(date(2023, 10, 1).Time))
+			report := analyze(opts, nil, selected, reactions)
+
+			if report.Until.Before(report.Since.Time) {
+				t.Fatalf("expected the period to end after its start, got %v..%v", report.Since, report.Until)
+			}
+			if !report.Since.Equal(tt.expectedSince) {
+				t.Errorf("expected since %v, got %v", tt.expectedSince, report.Since)
+			}
+			if !report.Until.Equal(period.Until.Time) {
+				t.Errorf("expected until %v, got %v", period.Until, report.Until)
+			}
+			if report.Totals.Posts != tt.expectedPosts || report.Totals.AnalyzedPosts != tt.expectedAnalyzed {
+				t.Errorf("expected %d posts and %d analyzed posts, got %d and %d", tt.expectedPosts, tt.expectedAnalyzed, report.Totals.Posts, report.Totals.AnalyzedPosts)
+			}
+			if report.Totals.Reactions != tt.expectedReactions {
+				t.Errorf("expected %d reactions, got %d", tt.expectedReactions, report.Totals.Reactions)
+			}
+		})
+	}
+}
+
+// redirectTransport sends the requests to a test server, whatever their host.
+type redirectTransport struct {
+	target *url.URL
+}
+
+func (t redirectTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = t.target.Scheme
+	req.URL.Host = t.target.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+// newTestClient returns a REST client sending its requests to handler.
+func newTestClient(t *testing.T, handler http.Handler) *gh.RESTClient {
+	t.Helper()
+
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	target, err := url.Parse(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client, err := gh.NewRESTClient(gh.ClientOptions{Host: "github.com", AuthToken: "token", Transport: redirectTransport{target: target}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+func TestFetchReactions(t *testing.T) {
+	repo := gh.Repository{Host: "github.com", Owner: "owner", Name: "repo"}
+
+	var posts []Post
+	for i := 1; i <= 6; i++ {
+		posts = append(posts, Post{Type: PostTypeIssue, Repository: repo, ID: strconv.Itoa(i), Link: strconv.Itoa(i)})
+	}
+
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(strings.Split(r.URL.Path, "/")[5])
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		// the first posts complete last
+		time.Sleep(time.Duration(len(posts)-id) * 5 * time.Millisecond)
+		fmt.Fprintf(w, `[{"content": "+1", "user": {"login": "user-%d"}}, {"content": "heart", "user": {"login": "user-%d"}}]`, id, id)
+	}))
+
+	reactions, err := fetchReactions(t.Context(), client, posts, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, r := range reactions {
+		got = append(got, r.Post.ID+":"+r.Reaction.Content)
+	}
+	expected := []string{"1:+1", "1:heart", "2:+1", "2:heart", "3:+1", "3:heart", "4:+1", "4:heart", "5:+1", "5:heart", "6:+1", "6:heart"}
+	if !slices.Equal(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestFetchReactionsError(t *testing.T) {
+	repo := gh.Repository{Host: "github.com", Owner: "owner", Name: "repo"}
+
+	var posts []Post
+	for i := 1; i <= 20; i++ {
+		posts = append(posts, Post{Type: PostTypeIssue, Repository: repo, ID: strconv.Itoa(i), Link: strconv.Itoa(i)})
+	}
+
+	var requests atomic.Int32
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if strings.Split(r.URL.Path, "/")[5] == "1" {
+			http.Error(w, `{"message": "Not Found"}`, http.StatusNotFound)
+			return
+		}
+
+		// the other requests are pending until they are canceled
+		select {
+		case <-r.Context().Done():
+		case <-time.After(10 * time.Second):
+			fmt.Fprint(w, `[]`)
+		}
+	}))
+
+	start := time.Now()
+	_, err := fetchReactions(t.Context(), client, posts, 4)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("expected the pending requests to be canceled, waited %v", elapsed)
+	}
+	if got := requests.Load(); got > 4 {
+		t.Errorf("expected at most 4 requests, one per worker, got %d", got)
+	}
+}
+
+func TestPostReactionsURI(t *testing.T) {
+	repo := gh.Repository{Host: "github.com", Owner: "owner", Name: "repo"}
+
+	tests := []struct {
+		postType      PostType
+		expected      string
+		expectedError bool
+	}{
+		{PostTypeIssue, "repos/owner/repo/issues/42/reactions?per_page=100", false},
+		{PostTypePullRequest, "repos/owner/repo/issues/42/reactions?per_page=100", false},
+		{PostTypeComment, "repos/owner/repo/issues/comments/42/reactions?per_page=100", false},
+		{PostTypeReviewComment, "repos/owner/repo/pulls/comments/42/reactions?per_page=100", false},
+		{PostTypeCommitComment, "repos/owner/repo/comments/42/reactions?per_page=100", false},
+		{PostTypeRelease, "repos/owner/repo/releases/42/reactions?per_page=100", false},
+		// their reactions are fetched with the GraphQL API
+		{PostTypeDiscussion, "", true},
+		{PostTypeDiscussionComment, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.postType), func(t *testing.T) {
+			got, err := Post{Type: tt.postType, Repository: repo, ID: "42"}.reactionsURI()
+			if tt.expectedError {
+				if err == nil {
+					t.Errorf("expected an error, got %q", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestFetchReleases(t *testing.T) {
+	repo := gh.Repository{Host: "github.com", Owner: "owner", Name: "repo"}
+
+	var pages []string
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := r.URL.Query().Get("page")
+		pages = append(pages, page)
+
+		switch page {
+		case "":
+			w.Header().Set("Link", `<https://api.github.com/repos/owner/repo/releases?per_page=100&page=2>; rel="next"`)
+			fmt.Fprint(w, `[
+				{"id": 5, "tag_name": "v5", "draft": true, "created_at": "2024-03-20T00:00:00Z", "author": {"login": "octocat"}},
+				{"id": 4, "name": "Version 4", "tag_name": "v4", "created_at": "2024-03-10T00:00:00Z", "published_at": "2024-03-15T00:00:00Z", "author": {"login": "octocat"}},
+				{"id": 3, "tag_name": "v3", "created_at": "2024-03-05T00:00:00Z", "published_at": "2024-03-06T00:00:00Z", "author": {"login": "hubot"}},
+				{"id": 2, "tag_name": "v2", "created_at": "2024-02-25T00:00:00Z", "published_at": "2024-03-02T00:00:00Z", "author": {"login": "octocat"}},
+				{"id": 1, "tag_name": "v1", "created_at": "2024-02-01T00:00:00Z", "published_at": "2024-02-01T00:00:00Z", "author": {"login": "octocat"}}
+			]`)
+		default:
+			fmt.Fprint(w, `[{"id": 0, "tag_name": "v0", "created_at": "2024-01-01T00:00:00Z", "published_at": "2024-01-01T00:00:00Z", "author": {"login": "octocat"}}]`)
+		}
+	}))
+
+	minDate := timeago.NewRelativeDate(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC))
+
+	tests := []struct {
+		name     string
+		author   string
+		expected []string
+	}{
+		// the draft and the release published before minDate are skipped, the one created before it is kept
+		{"All", "", []string{"Version 4", "v3", "v2"}},
+		{"Author", "OctoCat", []string{"Version 4", "v2"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pages = nil
+
+			var got []string
+			err := fetchReleases(t.Context(), client, repo, minDate, tt.author, func(p Post) {
+				if p.Type != PostTypeRelease || !p.CreatedAt.Equal(p.UpdatedAt.Time) {
+					t.Errorf("expected a release dated when it was published, got %v", p)
+				}
+				got = append(got, p.Content)
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !slices.Equal(got, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+			// the releases of the next page were created before the ones of the first page
+			if len(pages) != 1 {
+				t.Errorf("expected the first page only, got the pages %q", pages)
+			}
+		})
+	}
+}
+
+func TestRepositoriesSet(t *testing.T) {
+	tests := []struct {
+		name          string
+		host          string
+		values        []string
+		expected      string
+		expectedError bool
+	}{
+		{"Name", "github.com", []string{"owner/repo"}, "owner/repo", false},
+		{"Default host", "github.com", []string{"github.com/owner/repo"}, "owner/repo", false},
+		{"Duplicates", "github.com", []string{"owner/repo", "github.com/owner/repo", "owner/other"}, "owner/repo, owner/other", false},
+		{"Enterprise host", "ghe.example.com", []string{"owner/repo", "ghe.example.com/owner/other"}, "owner/repo, owner/other", false},
+		{"Other host", "github.com", []string{"ghe.example.com/owner/repo"}, "", true},
+		{"Public host with GH_HOST", "ghe.example.com", []string{"github.com/owner/repo"}, "", true},
+		{"Invalid", "github.com", []string{"repo"}, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("GH_HOST", tt.host)
+
+			var repos repositories
+			var err error
+			for _, value := range tt.values {
+				if err = repos.Set(value); err != nil {
+					break
+				}
+			}
+
+			if tt.expectedError {
+				if err == nil {
+					t.Errorf("expected an error, got %v", repos)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := repos.String(); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+			for _, repo := range repos {
+				if repo.Host != tt.host {
+					t.Errorf("expected the host %s, got %s", tt.host, repo.Host)
+				}
+			}
+		})
+	}
+}
+
+func TestRepositoriesReadFile(t *testing.T) {
+	t.Setenv("GH_HOST", "github.com")
+
+	write := func(t *testing.T, content string) string {
+		t.Helper()
+		name := filepath.Join(t.TempDir(), "repos.txt")
+		if err := os.WriteFile(name, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return name
+	}
+
+	t.Run("Comments and blank lines", func(t *testing.T) {
+		name := write(t, "# the CLI repositories\ncli/cli\n\n  cli/go-gh  \r\n# cli/archived\n\ngithub.com/cli/cli\n")
+
+		var repos repositories
+		if err := repos.ReadFile(name); err != nil {
+			t.Fatal(err)
+		}
+		if got, expected := repos.String(), "cli/cli, cli/go-gh"; got != expected {
+			t.Errorf("expected %v, got %v", expected, got)
+		}
+	})
+
+	t.Run("Invalid line", func(t *testing.T) {
+		name := write(t, "cli/cli\n\n# another host\nghe.example.com/owner/repo\n")
+
+		var repos repositories
+		err := repos.ReadFile(name)
+		if err == nil || !strings.Contains(err.Error(), name+":4:") {
+			t.Errorf("expected an error on line 4, got %v", err)
+		}
+	})
+
+	t.Run("Missing file", func(t *testing.T) {
+		var repos repositories
+		if err := repos.ReadFile(filepath.Join(t.TempDir(), "missing.txt")); !errors.Is(err, fs.ErrNotExist) {
+			t.Errorf("expected %v, got %v", fs.ErrNotExist, err)
+		}
+	})
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/ccoVeille/gh-reaction/internal/gh"
+)
+
+// orgOptions are the settings used to select the repositories of an organization.
+type orgOptions struct {
+	name         string
+	excludeForks bool
+	include      stringList
+	exclude      stringList
+}
+
+// match reports whether the repository name is selected by the include and exclude globs.
+func (o orgOptions) match(name string) bool {
+	if len(o.include) > 0 && !matchAny(o.include, name) {
+		return false
+	}
+	return !matchAny(o.exclude, name)
+}
+
+func matchAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		// the patterns are validated when parsing the flags
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// fetchOrgRepositories lists the repositories of the organization to analyze, the archived ones are ignored.
+func fetchOrgRepositories(ctx context.Context, client *gh.RESTClient, opts orgOptions) ([]gh.Repository, error) {
+	fmt.Fprintf(os.Stderr, "Looking for repositories of %s\n", opts.name)
+
+	// TODO use github.Repository
+	type orgRepository struct {
+		Name     string `json:"name"`
+		Archived bool   `json:"archived"`
+		Fork     bool   `json:"fork"`
+		Owner    struct {
+			Login string `json:"login"`
+		} `json:"owner"`
+	}
+
+	host := gh.DefaultHost()
+
+	var repos []gh.Repository
+	uri := fmt.Sprintf("orgs/%s/repos?type=all&per_page=100", opts.name)
+	for page, err := range gh.Paginate[orgRepository](ctx, client, uri) {
+		if err != nil {
+			return nil, err
+		}
+
+		for _, repo := range page {
+			if repo.Archived || (opts.excludeForks && repo.Fork) || !opts.match(repo.Name) {
+				continue
+			}
+
+			repos = append(repos, gh.Repository{
+				Host:  host,
+				Owner: repo.Owner.Login,
+				Name:  repo.Name,
+			})
+		}
+	}
+
+	if len(repos) == 0 {
+		return nil, fmt.Errorf("no repository to analyze in organization %s", opts.name)
+	}
+
+	fmt.Fprintf(os.Stderr, "✔️ found %d repositories\n", len(repos))
+	return repos, nil
+}
+
+// stringList is a list of values set with a repeatable flag.
+type stringList []string
+
+// String returns the values separated by commas.
+//
+// It satisfies the [flag.Value] and [fmt.Stringer] interface.
+func (s stringList) String() string {
+	return strings.Join(s, ", ")
+}
+
+// Set adds a value to the list.
+//
+// It satisfies the [flag.Value] interface.
+func (s *stringList) Set(value string) error {
+	*s = append(*s, value)
+	return nil
+}
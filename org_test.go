@@ -0,0 +1,31 @@
+package main
+
+import "testing"
+
+func TestOrgOptionsMatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     orgOptions
+		repo     string
+		expected bool
+	}{
+		{"No globs", orgOptions{}, "cli", true},
+		{"Included", orgOptions{include: stringList{"gh-*"}}, "gh-reaction", true},
+		{"Not included", orgOptions{include: stringList{"gh-*"}}, "cli", false},
+		{"One of the included", orgOptions{include: stringList{"go-*", "gh-*"}}, "gh-reaction", true},
+		{"Excluded", orgOptions{exclude: stringList{"*-archive"}}, "cli-archive", false},
+		{"Not excluded", orgOptions{exclude: stringList{"*-archive"}}, "cli", true},
+		{"Included then excluded", orgOptions{include: stringList{"gh-*"}, exclude: stringList{"*-archive"}}, "gh-archive", false},
+		{"Exact name", orgOptions{include: stringList{"cli"}}, "cli", true},
+		{"Character class", orgOptions{include: stringList{"go-[a-m]*"}}, "go-gh", true},
+		{"Outside the character class", orgOptions{include: stringList{"go-[a-m]*"}}, "go-pkg", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.opts.match(tt.repo); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
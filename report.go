@@ -0,0 +1,216 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/ccoVeille/gh-reaction/internal/gh"
+	"github.com/ccoVeille/gh-reaction/internal/github"
+	"github.com/ccoVeille/gh-reaction/internal/timeago"
+)
+
+// Report is the structured view of the analysis made by execute.
+//
+// Its JSON encoding is the documented output of the "json" format.
+type Report struct {
+	// Repository is the name of the analyzed repository, it's empty when several repositories are analyzed.
+	Repository string `json:"repository,omitempty"`
+	// Repositories holds the totals of each repository, the repositories with the most reactions first.
+	Repositories []RepositoryReport `json:"repositories"`
+	// Author is set when only the posts of the author were fetched, the totals are limited to them.
+	Author string `json:"author,omitempty"`
+	// Since and Until bound the period the reactions were given in, a zero Until means now.
+	Since timeago.RelativeDate `json:"since"`
+	Until timeago.RelativeDate `json:"until,omitzero"`
+	// PostsSince is the date the analyzed posts were updated or created since, as told by DateField.
+	PostsSince timeago.RelativeDate `json:"posts_since"`
+	DateField  dateField            `json:"date_field"`
+	// Totals cover all the repositories.
+	Totals ReportTotals `json:"totals"`
+
+	// The breakdowns are sorted by count (descending).
+	Reactions ValueCounts[string]      `json:"reactions"`
+	Posts     ValueCounts[Post]        `json:"posts"`
+	Authors   ValueCounts[github.User] `json:"authors"`
+	Users     ValueCounts[github.User] `json:"users"`
+
+	// Entries are the analyzed reactions, sorted by date (ascending) as done by [Reactions.Clean].
+	Entries Reactions `json:"entries"`
+
+	// Timeline is set when the reactions are bucketed by day, week or month.
+	Timeline *Timeline `json:"timeline,omitempty"`
+	// Heatmap is set when the reactions are counted per weekday and hour.
+	Heatmap *Heatmap `json:"heatmap,omitempty"`
+	// Comparison is set when the report is compared with another period.
+	Comparison *Comparison `json:"comparison,omitempty"`
+}
+
+// RepositoryReport holds the statistics of a single repository of a [Report].
+type RepositoryReport struct {
+	Repository string       `json:"repository"`
+	Totals     ReportTotals `json:"totals"`
+}
+
+// ReportTotals holds the counters of a [Report].
+type ReportTotals struct {
+	Posts              int `json:"posts"`
+	AnalyzedPosts      int `json:"analyzed_posts"`
+	PostsWithReactions int `json:"posts_with_reactions"`
+	Reactions          int `json:"reactions"`
+	Authors            int `json:"authors"`
+	Users              int `json:"users"`
+}
+
+func newReport(repos []gh.Repository, period timeago.DateRange, postsSince timeago.RelativeDate, author string, allPosts, posts []Post, reactions Reactions) Report {
+	if reactions == nil {
+		// always encode entries as a list
+		reactions = Reactions{}
+	}
+
+	r := Report{
+		Author:     author,
+		Since:      period.Since,
+		Until:      period.Until,
+		PostsSince: postsSince,
+		Totals:     newReportTotals(allPosts, posts, reactions),
+		Reactions:  reactions.Reactions().Sort(),
+		Posts:      reactions.Posts().Sort(),
+		Authors:    reactions.Authors().Sort(),
+		Users:      reactions.Users().Sort(),
+		Entries:    reactions,
+	}
+
+	if len(repos) == 1 {
+		r.Repository = gh.RepositoryName(repos[0])
+	}
+
+	for _, repo := range repos {
+		inRepo := func(p Post) bool {
+			return p.Repository == repo
+		}
+
+		r.Repositories = append(r.Repositories, RepositoryReport{
+			Repository: gh.RepositoryName(repo),
+			Totals: newReportTotals(
+				filter(allPosts, inRepo),
+				filter(posts, inRepo),
+				filter(reactions, func(r ReactionTo) bool { return inRepo(r.Post) }),
+			),
+		})
+	}
+
+	// the repositories with the most reactions first
+	slices.SortStableFunc(r.Repositories, func(a, b RepositoryReport) int {
+		return b.Totals.Reactions - a.Totals.Reactions
+	})
+
+	return r
+}
+
+func newReportTotals(allPosts, posts []Post, reactions Reactions) ReportTotals {
+	return ReportTotals{
+		Posts:              len(allPosts),
+		AnalyzedPosts:      len(posts),
+		PostsWithReactions: len(reactions.Posts()),
+		Reactions:          len(reactions),
+		Authors:            len(reactions.Authors()),
+		Users:              len(reactions.Users()),
+	}
+}
+
+// RepositoryNames returns the names of the repositories of the report.
+func (r Report) RepositoryNames() string {
+	names := make([]string, 0, len(r.Repositories))
+	for _, repo := range r.Repositories {
+		names = append(names, repo.Repository)
+	}
+	slices.Sort(names)
+	return strings.Join(names, ", ")
+}
+
+// filter returns the values matching keep, in a new slice.
+func filter[S ~[]E, E any](values S, keep func(E) bool) S {
+	var filtered S
+	for _, v := range values {
+		if keep(v) {
+			filtered = append(filtered, v)
+		}
+	}
+	return filtered
+}
+
+// outputFormat is the format used to render a [Report].
+type outputFormat string
+
+const (
+	outputFormatText outputFormat = "text"
+	outputFormatJSON outputFormat = "json"
+	outputFormatCSV  outputFormat = "csv"
+	outputFormatTSV  outputFormat = "tsv"
+	outputFormatMD   outputFormat = "markdown"
+)
+
+var outputFormats = []outputFormat{
+	outputFormatText,
+	outputFormatJSON,
+	outputFormatCSV,
+	outputFormatTSV,
+	outputFormatMD,
+}
+
+// String returns the name of the format.
+//
+// It satisfies the [flag.Value] and [fmt.Stringer] interface.
+func (f outputFormat) String() string {
+	return string(f)
+}
+
+// Set sets the format from its name.
+//
+// It satisfies the [flag.Value] interface.
+func (f *outputFormat) Set(value string) error {
+	for _, format := range outputFormats {
+		if strings.EqualFold(value, string(format)) {
+			*f = format
+			return nil
+		}
+	}
+	return fmt.Errorf("unsupported format %q, expected one of %v", value, outputFormats)
+}
+
+// renderOptions holds the settings shared by the formats.
+type renderOptions struct {
+	// top is the number of rows displayed in the top lists.
+	top int
+
+	// jq is a jq expression applied to the JSON report, it takes precedence over the format.
+	jq string
+
+	// template is a Go template applied to the JSON report, it takes precedence over the format.
+	template string
+}
+
+// Write renders the report in the format.
+func (f outputFormat) Write(w io.Writer, r Report, opts renderOptions) error {
+	switch {
+	case opts.jq != "":
+		return writeJQ(w, r, opts.jq)
+	case opts.template != "":
+		return writeTemplate(w, r, opts.template)
+	}
+
+	switch f {
+	case outputFormatMD:
+		return writeMarkdown(w, r, opts)
+	case outputFormatJSON:
+		return writeJSON(w, r)
+	case outputFormatCSV:
+		return writeCSV(w, r, ',')
+	case outputFormatTSV:
+		return writeCSV(w, r, '\t')
+	default:
+		return writeText(w, r, opts)
+	}
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"encoding/csv"
+	"io"
+	"time"
+
+	"github.com/ccoVeille/gh-reaction/internal/gh"
+)
+
+// csvHeader is the header of the "csv" and "tsv" formats.
+var csvHeader = []string{
+	"reaction_time",
+	"reactor",
+	"reaction",
+	"post_type",
+	"post_author",
+	"post_date",
+	"post_link",
+	"post_preview",
+	"repository",
+}
+
+// writeCSV renders one row per reaction, in the order of the report entries.
+func writeCSV(w io.Writer, r Report, comma rune) error {
+	cw := csv.NewWriter(w)
+	cw.Comma = comma
+
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+
+	for _, entry := range r.Entries {
+		err := cw.Write([]string{
+			entry.Reaction.CreatedAt.Format(time.RFC3339),
+			entry.Reaction.User.GetLogin(),
+			entry.Reaction.Content,
+			string(entry.Post.Type),
+			entry.Post.Author.GetLogin(),
+			entry.Post.Date.Format(time.RFC3339),
+			entry.Post.Link,
+			entry.Post.ContentPreview(),
+			gh.RepositoryName(entry.Post.Repository),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"slices"
+	"testing"
+)
+
+func TestWriteCSV(t *testing.T) {
+	expected := [][]string{
+		csvHeader,
+		{"2024-03-02T10:00:00Z", "hubot", "+1", "issue", "octocat", "2024-03-02T09:00:00Z", "https://github.com/owner/repo/issues/1", "Add a | separator", "owner/repo"},
+		{"2024-03-03T11:00:00Z", "monalisa", "heart", "issue", "octocat", "2024-03-02T09:00:00Z", "https://github.com/owner/repo/issues/1", "Add a | separator", "owner/repo"},
+		{"2024-03-04T12:00:00Z", "octocat", "+1", "comment", "hubot", "2024-03-03T10:00:00Z", "https://github.com/owner/repo/issues/1#issuecomment-2", "Looks *good*, thanks", "owner/repo"},
+	}
+
+	tests := []struct {
+		name  string
+		comma rune
+	}{
+		{"CSV", ','},
+		{"TSV", '\t'},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := writeCSV(&buf, newTestReport(), tt.comma); err != nil {
+				t.Fatal(err)
+			}
+
+			r := csv.NewReader(&buf)
+			r.Comma = tt.comma
+			got, err := r.ReadAll()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(got) != len(expected) {
+				t.Fatalf("expected %d rows, got %d: %v", len(expected), len(got), got)
+			}
+			for i := range expected {
+				if !slices.Equal(got[i], expected[i]) {
+					t.Errorf("row %d: expected %v, got %v", i, expected[i], got[i])
+				}
+			}
+		})
+	}
+}
+
+func TestWriteCSVQuoting(t *testing.T) {
+	report := newTestReport()
+	report.Entries = report.Entries[:1]
+	report.Entries[0].Post.Content = "a, \"quoted\" value"
+
+	var buf bytes.Buffer
+	if err := writeCSV(&buf, report, ','); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "reaction_time,reactor,reaction,post_type,post_author,post_date,post_link,post_preview,repository\n" +
+		"2024-03-02T10:00:00Z,hubot,+1,issue,octocat,2024-03-02T09:00:00Z,https://github.com/owner/repo/issues/1,\"a, \"\"quoted\"\" value\",owner/repo\n"
+	if got := buf.String(); got != expected {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+}
@@ -0,0 +1,210 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ccoVeille/gh-reaction/internal/github"
+	"github.com/ccoVeille/gh-reaction/internal/timeago"
+)
+
+// markdownHeaderRepositories is the maximum number of repositories named in the header of the Markdown report.
+const markdownHeaderRepositories = 3
+
+// writeMarkdown renders the report as Markdown tables, suitable for wikis and discussions.
+func writeMarkdown(w io.Writer, r Report, opts renderOptions) error {
+	period := markdownPeriod(r.Since, r.Until)
+
+	repositories := r.RepositoryNames()
+	if len(r.Repositories) > markdownHeaderRepositories {
+		// the repositories with the most reactions are listed in the totals table
+		repositories = fmt.Sprintf("%d repositories", len(r.Repositories))
+	}
+
+	if r.Author != "" {
+		fmt.Fprintf(w, "## Reactions to %s on %s %s\n\n", r.Author, repositories, period)
+	} else {
+		fmt.Fprintf(w, "## Reactions on %s %s\n\n", repositories, period)
+	}
+
+	if len(r.Repositories) > 1 {
+		maxSizeRepo := len("Repository")
+		for _, repo := range r.Repositories {
+			maxSizeRepo = max(maxSizeRepo, len(repo.Repository))
+		}
+
+		fmt.Fprintf(w, "| %-*s | Messages | Analyzed | With reactions | Reactions |\n", maxSizeRepo, "Repository")
+		fmt.Fprintf(w, "| %s | -------: | -------: | -------------: | --------: |\n", strings.Repeat("-", maxSizeRepo))
+		// the repositories are sorted by reactions (descending)
+		topRepositories := r.Repositories[:min(len(r.Repositories), max(opts.top, 0))]
+		for _, repo := range topRepositories {
+			fmt.Fprintf(w, "| %-*s | %8d | %8d | %14d | %9d |\n", maxSizeRepo, repo.Repository, repo.Totals.Posts, repo.Totals.AnalyzedPosts, repo.Totals.PostsWithReactions, repo.Totals.Reactions)
+		}
+		fmt.Fprintf(w, "| %-*s | %8d | %8d | %14d | %9d |\n", maxSizeRepo, "**Total**", r.Totals.Posts, r.Totals.AnalyzedPosts, r.Totals.PostsWithReactions, r.Totals.Reactions)
+		if r.Comparison != nil {
+			fmt.Fprintf(w, "| %-*s ", maxSizeRepo, "**Change**")
+			writeMarkdownChanges(w, r.Totals, r.Comparison.Totals)
+		}
+		fmt.Fprintln(w)
+	} else {
+		fmt.Fprintln(w, "| Messages | Analyzed | With reactions | Reactions |")
+		fmt.Fprintln(w, "| -------: | -------: | -------------: | --------: |")
+		fmt.Fprintf(w, "| %8d | %8d | %14d | %9d |\n", r.Totals.Posts, r.Totals.AnalyzedPosts, r.Totals.PostsWithReactions, r.Totals.Reactions)
+		if r.Comparison != nil {
+			writeMarkdownChanges(w, r.Totals, r.Comparison.Totals)
+		}
+		fmt.Fprintln(w)
+	}
+
+	if r.Comparison != nil {
+		fmt.Fprintf(w, "Compared with the period %s.\n\n", markdownPeriod(r.Comparison.Since, r.Comparison.Until))
+	}
+
+	if r.Totals.PostsWithReactions == 0 {
+		return nil
+	}
+
+	writeMarkdownTable(w, "Reactions", "Reaction", r.Reactions, func(s string) string {
+		return s
+	}, diffs(r, func(c Comparison) []ValueCountDiff[string] { return c.Reactions }, func(s string) string { return s }))
+
+	if r.Timeline != nil {
+		writeMarkdownTimeline(w, *r.Timeline, r.Reactions)
+	}
+
+	if r.Heatmap != nil {
+		writeMarkdownHeatmap(w, *r.Heatmap)
+	}
+
+	writeMarkdownTable(w, "Top messages", "Message", r.Posts.Top(opts.top), func(p Post) string {
+		return fmt.Sprintf("[%s](%s) (%s by %s)", escapeMarkdown(p.ContentPreview()), p.Link, p.Type, markdownUser(p.Author))
+	}, nil)
+
+	writeMarkdownTable(w, "Top users who got reactions", "User", r.Authors.Top(opts.top), markdownUser,
+		diffs(r, func(c Comparison) []ValueCountDiff[github.User] { return c.Authors }, userLogin))
+
+	writeMarkdownTable(w, "Top users who reacted", "User", r.Users.Top(opts.top), markdownUser,
+		diffs(r, func(c Comparison) []ValueCountDiff[github.User] { return c.Users }, userLogin))
+
+	return nil
+}
+
+// writeMarkdownTable prints a two columns table, the values are aligned thanks to
+// [ValueCounts.MaxSizeCount] and [ValueCounts.MaxSizeValue].
+//
+// A third column shows the change of each value when change is not nil.
+func writeMarkdownTable[T any](w io.Writer, title, header string, values ValueCounts[T], format func(T) string, change func(T) string) {
+	if len(values) == 0 {
+		return
+	}
+
+	const countHeader = "Count"
+	const changeHeader = "Change"
+	maxSizeCount := max(values.MaxSizeCount(), len(countHeader))
+	maxSizeValue := max(values.MaxSizeValue(format), len(header))
+
+	fmt.Fprintf(w, "### %s\n\n", title)
+	fmt.Fprintf(w, "| %*s | %-*s |", maxSizeCount, countHeader, maxSizeValue, header)
+	if change != nil {
+		fmt.Fprintf(w, " %s |", changeHeader)
+	}
+	fmt.Fprintf(w, "\n| %s: | %s |", strings.Repeat("-", maxSizeCount-1), strings.Repeat("-", maxSizeValue))
+	if change != nil {
+		fmt.Fprintf(w, " %s: |", strings.Repeat("-", len(changeHeader)-1))
+	}
+	fmt.Fprintln(w)
+	for _, v := range values {
+		fmt.Fprintf(w, "| %*s | %-*s |", maxSizeCount, strconv.Itoa(v.Count), maxSizeValue, format(v.Value))
+		if change != nil {
+			fmt.Fprintf(w, " %*s |", len(changeHeader), change(v.Value))
+		}
+		fmt.Fprintln(w)
+	}
+	fmt.Fprintln(w)
+}
+
+// writeMarkdownChanges prints a row of the totals table with the changes from the compared period.
+//
+// The messages of the compared period overlap the ones of the period, their counts are not compared.
+func writeMarkdownChanges(w io.Writer, totals, previous ReportTotals) {
+	fmt.Fprintf(w, "| %8s | %8s | %14s | %9s |\n",
+		"",
+		"",
+		formatChange(totals.PostsWithReactions, previous.PostsWithReactions),
+		formatChange(totals.Reactions, previous.Reactions),
+	)
+}
+
+// markdownPeriod returns the period as "since 2024-01-01", or "from 2024-01-01 to 2024-03-31".
+func markdownPeriod(since, until timeago.RelativeDate) string {
+	if until.IsZero() {
+		return "since " + since.Format(time.DateOnly)
+	}
+	// the end of the period is excluded
+	return fmt.Sprintf("from %s to %s", since.Format(time.DateOnly), until.Add(-time.Nanosecond).Format(time.DateOnly))
+}
+
+// writeMarkdownTimeline prints the number of reactions of each bucket, per emoji, followed by a sparkline.
+func writeMarkdownTimeline(w io.Writer, t Timeline, reactions ValueCounts[string]) {
+	header := strings.ToUpper(t.Unit.String()[:1]) + t.Unit.String()[1:]
+	maxSizeLabel := max(len(t.Unit.label(time.Now())), len(header))
+
+	fmt.Fprintf(w, "### Reactions per %s (%s)\n\n", t.Unit, t.TimeZone)
+	fmt.Fprintf(w, "| %-*s |", maxSizeLabel, header)
+	for _, reaction := range reactions {
+		fmt.Fprintf(w, " %s |", reaction.Value)
+	}
+	fmt.Fprintln(w, " Total |")
+
+	fmt.Fprintf(w, "| %s |", strings.Repeat("-", maxSizeLabel))
+	for range reactions {
+		fmt.Fprint(w, " -: |")
+	}
+	fmt.Fprintln(w, " ----: |")
+
+	for _, bucket := range t.Buckets {
+		fmt.Fprintf(w, "| %-*s |", maxSizeLabel, t.Unit.label(bucket.Start))
+		for _, reaction := range reactions {
+			fmt.Fprintf(w, " %d |", countOf(bucket.Reactions, reaction.Value))
+		}
+		fmt.Fprintf(w, " %5d |\n", bucket.Total)
+	}
+	fmt.Fprintf(w, "\n`%s`\n\n", sparkline(t.Counts()))
+}
+
+// writeMarkdownHeatmap prints the heatmap rendered by [writeTextHeatmap] in a code block.
+//
+// Unlike the other sections, it's not a table: a table of 24 hours doesn't fit the width of most renderers,
+// and the shaded blocks only line up with a monospace font.
+func writeMarkdownHeatmap(w io.Writer, h Heatmap) {
+	fmt.Fprintf(w, "### Reactions per weekday and hour (%s)\n\n", h.TimeZone)
+	fmt.Fprintln(w, "```text")
+	writeTextHeatmap(w, h)
+	fmt.Fprint(w, "```\n\n")
+}
+
+// markdownUser returns a link to the user profile.
+func markdownUser(u github.User) string {
+	if u.Login == nil {
+		return u.String()
+	}
+	return fmt.Sprintf("[@%s](%s)", *u.Login, u.GitHubURL())
+}
+
+var markdownEscaper = strings.NewReplacer(
+	`|`, `\|`,
+	`[`, `\[`,
+	`]`, `\]`,
+	"`", "\\`",
+	`*`, `\*`,
+	`_`, `\_`,
+	`<`, `&lt;`,
+)
+
+// escapeMarkdown escapes the characters that would break a link label in a table cell.
+func escapeMarkdown(s string) string {
+	return markdownEscaper.Replace(s)
+}
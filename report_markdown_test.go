@@ -0,0 +1,64 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestEscapeMarkdown(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected string
+	}{
+		{"Plain", "Looks good", "Looks good"},
+		{"Table separator", "a | b", `a \| b`},
+		{"Link", "[link](url)", `\[link\](url)`},
+		{"Emphasis", "*bold* and _italic_", `\*bold\* and \_italic\_`},
+		{"Code", "`code`", "\\`code\\`"},
+		{"HTML", "<details>", "&lt;details>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := escapeMarkdown(tt.value); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeMarkdown(&buf, newTestReport(), renderOptions{top: 5}); err != nil {
+		t.Fatal(err)
+	}
+
+	assertGolden(t, "report.md", buf.Bytes())
+}
+
+func TestWriteMarkdownTop(t *testing.T) {
+	tests := []struct {
+		top      int
+		expected int
+	}{
+		{1, 1},
+		{2, 2},
+		{5, 3},
+	}
+
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		if err := writeMarkdown(&buf, newTestReport(), renderOptions{top: tt.top}); err != nil {
+			t.Fatal(err)
+		}
+
+		// the rows of the table following the title, after its header and separator
+		_, section, _ := strings.Cut(buf.String(), "### Top users who reacted\n\n")
+		table, _, _ := strings.Cut(section, "\n\n")
+		if got := strings.Count(table, "\n") - 1; got != tt.expected {
+			t.Errorf("top %d: expected %d rows, got %d in %q", tt.top, tt.expected, got, table)
+		}
+	}
+}
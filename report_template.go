@@ -0,0 +1,43 @@
+package main
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/cli/go-gh/v2/pkg/jq"
+	"github.com/cli/go-gh/v2/pkg/template"
+	"github.com/cli/go-gh/v2/pkg/term"
+)
+
+// writeJQ filters the JSON report with a jq expression, the same way "gh api --jq" does.
+func writeJQ(w io.Writer, r Report, expr string) error {
+	var buf bytes.Buffer
+	if err := writeJSON(&buf, r); err != nil {
+		return err
+	}
+
+	return jq.Evaluate(&buf, w, expr)
+}
+
+// writeTemplate renders the JSON report with a Go template, the same way "gh api --template" does.
+func writeTemplate(w io.Writer, r Report, tmpl string) error {
+	var buf bytes.Buffer
+	if err := writeJSON(&buf, r); err != nil {
+		return err
+	}
+
+	terminal := term.FromEnv()
+	width, _, err := terminal.Size()
+	if err != nil {
+		width = 80
+	}
+
+	t := template.New(w, width, terminal.IsColorEnabled())
+	if err := t.Parse(tmpl); err != nil {
+		return err
+	}
+	if err := t.Execute(&buf); err != nil {
+		return err
+	}
+	return t.Flush()
+}
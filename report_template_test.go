@@ -0,0 +1,74 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWriteJQ(t *testing.T) {
+	tests := []struct {
+		name     string
+		expr     string
+		expected string
+	}{
+		{"Total", ".totals.reactions", "3\n"},
+		{"String", ".repository", "owner/repo\n"},
+		{"Users", `.users[] | "\(.count) \(.value.login)"`, "1 monalisa\n1 octocat\n1 hubot\n"},
+		{"Entries", `[.entries[].post.type] | unique | join(",")`, "comment,issue\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := writeJQ(&buf, newTestReport(), tt.expr); err != nil {
+				t.Fatal(err)
+			}
+			if got := buf.String(); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+
+	if err := writeJQ(&bytes.Buffer{}, newTestReport(), ".totals["); err == nil {
+		t.Error("expected an error for an invalid expression")
+	}
+}
+
+func TestWriteTemplate(t *testing.T) {
+	tests := []struct {
+		name     string
+		tmpl     string
+		expected string
+	}{
+		{"Total", "{{.totals.reactions}} reactions", "3 reactions"},
+		{"Posts", `{{range .posts}}{{.count}} {{.value.link}}{{"\n"}}{{end}}`, "2 https://github.com/owner/repo/issues/1\n1 https://github.com/owner/repo/issues/1#issuecomment-2\n"},
+		{"Reactions", `{{range .reactions}}{{.value}}{{end}}`, "👍❤️"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := writeTemplate(&buf, newTestReport(), tt.tmpl); err != nil {
+				t.Fatal(err)
+			}
+			if got := buf.String(); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+
+	if err := writeTemplate(&bytes.Buffer{}, newTestReport(), "{{.totals"); err == nil {
+		t.Error("expected an error for an invalid template")
+	}
+}
+
+func TestOutputFormatWritePrecedence(t *testing.T) {
+	var buf bytes.Buffer
+	err := outputFormatMD.Write(&buf, newTestReport(), renderOptions{top: 5, jq: ".totals.posts"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); got != "2\n" {
+		t.Errorf("expected the jq expression to take precedence over the format, got %q", got)
+	}
+}
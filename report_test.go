@@ -0,0 +1,166 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ccoVeille/gh-reaction/internal/gh"
+	"github.com/ccoVeille/gh-reaction/internal/github"
+	"github.com/ccoVeille/gh-reaction/internal/timeago"
+)
+
+var update = flag.Bool("update", false, "update the golden files of testdata")
+
+// newTestReport returns the report of a single repository with fixed dates, shared by the tests of the formats.
+func newTestReport() Report {
+	repo := gh.Repository{Host: "github.com", Owner: "owner", Name: "repo"}
+	date := func(day, hour int) github.Time {
+		return github.Time{Time: time.Date(2024, 3, day, hour, 0, 0, 0, time.UTC)}
+	}
+	user := func(login, name string) github.User {
+		u := newUser(login, "User")
+		if name != "" {
+			u.Name = &name
+		}
+		return u
+	}
+
+	issue := Post{
+		Type:       PostTypeIssue,
+		Repository: repo,
+		Date:       date(2, 9),
+		CreatedAt:  date(1, 9),
+		UpdatedAt:  date(2, 9),
+		Content:    "Add a | separator",
+		Author:     user("octocat", "The Octocat"),
+		Link:       "https://github.com/owner/repo/issues/1",
+		ID:         "1",
+	}
+	comment := Post{
+		Type:       PostTypeComment,
+		Repository: repo,
+		Date:       date(3, 10),
+		CreatedAt:  date(3, 10),
+		UpdatedAt:  date(3, 10),
+		Content:    "> quoted\nLooks *good*, thanks",
+		Author:     user("hubot", ""),
+		Link:       "https://github.com/owner/repo/issues/1#issuecomment-2",
+		ID:         "2",
+	}
+
+	var reactions Reactions
+	for _, r := range []struct {
+		post    Post
+		user    github.User
+		content string
+		date    github.Time
+	}{
+		{issue, user("hubot", ""), "+1", date(2, 10)},
+		{issue, user("monalisa", "Mona Lisa"), "heart", date(3, 11)},
+		{comment, user("octocat", "The Octocat"), "+1", date(4, 12)},
+	} {
+		var reaction ReactionTo
+		reaction.Post = r.post
+		reaction.Reaction.User = r.user
+		reaction.Reaction.Content = r.content
+		reaction.Reaction.CreatedAt = r.date
+		reactions = append(reactions, reaction)
+	}
+
+	period := timeago.DateRange{
+		Since: timeago.NewRelativeDate(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)),
+		Until: timeago.NewRelativeDate(time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)),
+	}
+	posts := []Post{comment, issue}
+
+	report := newReport([]gh.Repository{repo}, period, period.Since, "", posts, posts, reactions)
+	report.DateField = dateFieldUpdated
+	return report
+}
+
+// assertGolden compares got with the content of the golden file of testdata, it updates the file with -update.
+func assertGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(path, got, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	expected, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, expected) {
+		t.Errorf("expected %s, got %s", expected, got)
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeJSON(&buf, newTestReport()); err != nil {
+		t.Fatal(err)
+	}
+
+	assertGolden(t, "report.json", buf.Bytes())
+}
+
+func TestWriteJSONRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeJSON(&buf, newTestReport()); err != nil {
+		t.Fatal(err)
+	}
+
+	// the fields read by the scripts using the report
+	var decoded struct {
+		Repository string `json:"repository"`
+		Totals     struct {
+			Reactions int `json:"reactions"`
+		} `json:"totals"`
+		Users []struct {
+			Value struct {
+				Login string `json:"login"`
+				URL   string `json:"url"`
+			} `json:"value"`
+			Count int `json:"count"`
+		} `json:"users"`
+		Entries []struct {
+			Reaction struct {
+				Content   string    `json:"content"`
+				CreatedAt time.Time `json:"created_at"`
+			} `json:"reaction"`
+			Post struct {
+				Repository string `json:"repository"`
+				Type       string `json:"type"`
+				Preview    string `json:"preview"`
+			} `json:"post"`
+		} `json:"entries"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	if decoded.Repository != "owner/repo" || decoded.Totals.Reactions != 3 {
+		t.Errorf("unexpected repository %q and %d reactions", decoded.Repository, decoded.Totals.Reactions)
+	}
+	if len(decoded.Users) != 3 || decoded.Users[0].Value.URL != "https://github.com/"+decoded.Users[0].Value.Login {
+		t.Errorf("unexpected users %+v", decoded.Users)
+	}
+	if len(decoded.Entries) != 3 {
+		t.Fatalf("expected 3 entries, got %d", len(decoded.Entries))
+	}
+	last := decoded.Entries[2]
+	if last.Reaction.Content != "+1" || !last.Reaction.CreatedAt.Equal(time.Date(2024, 3, 4, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected reaction %+v", last.Reaction)
+	}
+	if last.Post.Repository != "owner/repo" || last.Post.Type != "comment" || last.Post.Preview != "Looks *good*, thanks" {
+		t.Errorf("unexpected post %+v", last.Post)
+	}
+}
@@ -0,0 +1,214 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ccoVeille/gh-reaction/internal/github"
+)
+
+// writeText renders the report for humans.
+func writeText(w io.Writer, r Report, opts renderOptions) error {
+	if r.Totals.Posts == 0 {
+		fmt.Fprintln(w, "\nNo posts found since ", r.Since.String())
+		return nil
+	}
+
+	fmt.Fprintln(w, "Stats since", r.Since)
+	if !r.Until.IsZero() {
+		fmt.Fprintln(w, "Stats before", r.Until)
+	}
+	if !r.PostsSince.Equal(r.Since.Time) {
+		fmt.Fprintln(w, "On messages updated since", r.PostsSince)
+	}
+	if r.Comparison != nil {
+		fmt.Fprintln(w, "Compared with stats since", r.Comparison.Since, "before", r.Comparison.Until)
+	}
+	messages := "messages"
+	if r.Author != "" {
+		messages = "messages by " + r.Author
+	}
+	// the messages of the compared period overlap the ones of the period, their counts are not compared
+	if len(r.Repositories) > 1 {
+		fmt.Fprintln(w, r.Totals.Posts, messages, "on", len(r.Repositories), "repositories")
+	} else {
+		fmt.Fprintln(w, r.Totals.Posts, messages, "on repository")
+	}
+	fmt.Fprintln(w, r.Totals.AnalyzedPosts, "analyzed messages")
+	fmt.Fprintln(w, r.Totals.PostsWithReactions, "messages with reactions"+r.change(func(t ReportTotals) int { return t.PostsWithReactions }))
+	fmt.Fprintln(w)
+
+	if len(r.Repositories) > 1 {
+		topRepositories := r.Repositories[:min(len(r.Repositories), max(opts.top, 0))]
+		if len(r.Repositories) > len(topRepositories) {
+			fmt.Fprintln(w, "Repositories with most reactions:")
+		} else {
+			fmt.Fprintln(w, "Stats per repository:")
+		}
+		writeTextRepositories(w, topRepositories)
+	}
+
+	if r.Totals.PostsWithReactions == 0 {
+		return nil
+	}
+
+	reactionChange := diffs(r, func(c Comparison) []ValueCountDiff[string] { return c.Reactions }, func(s string) string { return s })
+	var reactionDetails []string
+	for _, reaction := range r.Reactions {
+		detail := fmt.Sprintf("%d: %s", reaction.Count, reaction.Value)
+		if reactionChange != nil {
+			detail += fmt.Sprintf(" (%s)", reactionChange(reaction.Value))
+		}
+		reactionDetails = append(reactionDetails, detail)
+	}
+	fmt.Fprintf(w, "Total reactions: %d%s (%s)\n\n", r.Totals.Reactions, r.change(func(t ReportTotals) int { return t.Reactions }), strings.Join(reactionDetails, " "))
+
+	if r.Timeline != nil {
+		writeTextTimeline(w, *r.Timeline, r.Reactions)
+	}
+
+	if r.Heatmap != nil {
+		fmt.Fprintf(w, "Reactions per weekday and hour (%s):\n", r.Heatmap.TimeZone)
+		writeTextHeatmap(w, *r.Heatmap)
+		fmt.Fprintln(w)
+	}
+
+	topPosts := r.Posts.Top(opts.top)
+	if len(r.Posts) > len(topPosts) {
+		fmt.Fprintln(w, "Messages with most reactions:")
+	} else {
+		fmt.Fprintln(w, "Messages with reactions:")
+	}
+
+	for _, post := range topPosts {
+		fmt.Fprintf(w, "Reactions:    %d\n", post.Count)
+		fmt.Fprint(w, post.Value.String())
+		fmt.Fprintln(w)
+	}
+	fmt.Fprintln(w)
+
+	topAuthors := r.Authors.Top(opts.top)
+	if len(r.Authors) > len(topAuthors) {
+		fmt.Fprintln(w, "Total users who got reactions:", len(r.Authors))
+		fmt.Fprintln(w, "\nTop users who got reactions:")
+	} else {
+		fmt.Fprintln(w, "Users who got reactions:")
+	}
+	writeTextUsers(w, topAuthors, diffs(r, func(c Comparison) []ValueCountDiff[github.User] { return c.Authors }, userLogin))
+
+	topUsers := r.Users.Top(opts.top)
+	if len(r.Users) > len(topUsers) {
+		fmt.Fprintln(w, "Total users who reacted:", len(r.Users))
+		fmt.Fprintln(w, "Top users who reacted:")
+	} else {
+		fmt.Fprintln(w, "Users who reacted:", len(r.Users))
+	}
+	writeTextUsers(w, topUsers, diffs(r, func(c Comparison) []ValueCountDiff[github.User] { return c.Users }, userLogin))
+
+	fmt.Fprintln(w, "Last reactions:")
+	for _, reaction := range r.Entries {
+		fmt.Fprint(w, reaction.String())
+		fmt.Fprintln(w)
+	}
+
+	return nil
+}
+
+// writeTextRepositories prints the totals of each repository, aligned on the counts.
+func writeTextRepositories(w io.Writer, repos []RepositoryReport) {
+	var maxSizeRepo, maxSizeCount int
+	for _, repo := range repos {
+		maxSizeRepo = max(maxSizeRepo, len(repo.Repository))
+		maxSizeCount = max(maxSizeCount, len(strconv.Itoa(repo.Totals.Reactions)))
+	}
+
+	for _, repo := range repos {
+		fmt.Fprintf(w, "%*d reactions %-*s (%d messages, %d analyzed, %d with reactions)\n",
+			maxSizeCount, repo.Totals.Reactions, maxSizeRepo, repo.Repository,
+			repo.Totals.Posts, repo.Totals.AnalyzedPosts, repo.Totals.PostsWithReactions)
+	}
+	fmt.Fprintln(w)
+}
+
+// writeTextTimeline prints the number of reactions of each bucket, per emoji, with a bar chart and a sparkline.
+func writeTextTimeline(w io.Writer, t Timeline, reactions ValueCounts[string]) {
+	const (
+		cellWidth = 4
+		barWidth  = 30
+	)
+
+	maxTotal := slices.Max(append(t.Counts(), 0))
+	maxSizeLabel := len(t.Unit.label(time.Now()))
+
+	fmt.Fprintf(w, "Reactions per %s (%s):\n", t.Unit, t.TimeZone)
+	fmt.Fprintf(w, "%*s", maxSizeLabel, "")
+	for _, reaction := range reactions {
+		// the emojis are two columns wide in a terminal
+		fmt.Fprintf(w, "%*s%s", cellWidth-2, "", reaction.Value)
+	}
+	fmt.Fprintln(w, " Total")
+
+	for _, bucket := range t.Buckets {
+		fmt.Fprint(w, t.Unit.label(bucket.Start))
+		for _, reaction := range reactions {
+			fmt.Fprintf(w, "%*d", cellWidth, countOf(bucket.Reactions, reaction.Value))
+		}
+		fmt.Fprintf(w, " %5d %s\n", bucket.Total, bar(bucket.Total, maxTotal, barWidth))
+	}
+	fmt.Fprintln(w, sparkline(t.Counts()))
+	fmt.Fprintln(w)
+}
+
+// writeTextHeatmap prints the heatmap as a grid of shaded blocks, two columns per hour.
+func writeTextHeatmap(w io.Writer, h Heatmap) {
+	fmt.Fprint(w, "    ")
+	for hour := 0; hour < 24; hour += 3 {
+		fmt.Fprintf(w, "%-6s", fmt.Sprintf("%02d", hour))
+	}
+	fmt.Fprintln(w, " Total")
+
+	for _, day := range h.Days {
+		fmt.Fprintf(w, "%-4s", day.Weekday[:3])
+		for _, count := range day.Hours {
+			fmt.Fprint(w, strings.Repeat(string(h.shade(count)), 2))
+		}
+		fmt.Fprintf(w, " %d\n", day.Total)
+	}
+
+	fmt.Fprintf(w, "%c%c%c%c fewer to more reactions per hour, up to %d\n",
+		heatmapShades[1], heatmapShades[2], heatmapShades[3], heatmapShades[4], h.Max)
+}
+
+// countOf returns the count of the value, or zero when the value is missing.
+func countOf[T comparable](values ValueCounts[T], value T) int {
+	for _, v := range values {
+		if v.Value == value {
+			return v.Count
+		}
+	}
+	return 0
+}
+
+// writeTextUsers prints the users aligned on their counts and logins, followed by their change when set.
+func writeTextUsers(w io.Writer, users ValueCounts[github.User], change func(github.User) string) {
+	maxSizeCount := users.MaxSizeCount()
+	maxSizeLogin := users.MaxSizeValue(func(u github.User) string {
+		if u.Login == nil {
+			return ""
+		}
+		return *u.Login
+	})
+
+	for _, user := range users {
+		fmt.Fprintf(w, "%*s %-*s %s", maxSizeCount, strconv.Itoa(user.Count), maxSizeLogin, user.Value, user.Value.GitHubURL())
+		if change != nil {
+			fmt.Fprintf(w, " (%s)", change(user.Value))
+		}
+		fmt.Fprintln(w)
+	}
+	fmt.Fprintln(w)
+}
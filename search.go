@@ -0,0 +1,167 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/ccoVeille/gh-reaction/internal/gh"
+	"github.com/ccoVeille/gh-reaction/internal/github"
+	"github.com/ccoVeille/gh-reaction/internal/timeago"
+)
+
+// searchMaxResults is the maximum number of results the search API returns for a query.
+const searchMaxResults = 1000
+
+// fetchAuthenticatedUser returns the login of the authenticated user.
+func fetchAuthenticatedUser(ctx context.Context, client *gh.RESTClient) (string, error) {
+	var user github.User
+	if err := client.Get(ctx, "user", &user); err != nil {
+		return "", err
+	}
+	return user.GetLogin(), nil
+}
+
+// fetchUserPosts fetches the issues, pull requests and comments of the author updated since minDate,
+// in all the repositories, using the search API.
+//
+// The search API doesn't find the review comments, commit comments, releases and discussions.
+func fetchUserPosts(ctx context.Context, client *gh.RESTClient, author string, minDate timeago.RelativeDate) ([]Post, error) {
+	fmt.Fprintf(os.Stderr, "Looking for posts of %s on all repositories since %s\n", author, minDate.String())
+
+	var posts []Post
+
+	ctx, spin := startSpinner(ctx, "searching posts")
+
+	// the posts updated after the end of the period may have got reactions in the period
+	maxDate := time.Now().UTC().Truncate(time.Second)
+
+	// Fetch issues and PRs created by the user
+	err := searchIssues(ctx, client, "author:"+author, minDate.Time, maxDate, func(issue restIssue) error {
+		repo, err := issue.repository()
+		if err != nil {
+			return err
+		}
+
+		postType := PostTypeIssue
+		if issue.PullRequest != nil {
+			postType = PostTypePullRequest
+		}
+
+		posts = append(posts, Post{
+			Repository: repo,
+			Type:       postType,
+			CreatedAt:  issue.CreatedAt,
+			UpdatedAt:  issue.UpdatedAt,
+			Content:    issue.Title,
+			Author:     issue.Author,
+			Link:       issue.Link,
+			ID:         strconv.Itoa(issue.Number),
+		})
+		spin.Progress("fetched %d posts", len(posts))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Fetch comments made by the user, through the issues and PRs the user commented on
+	err = searchIssues(ctx, client, "commenter:"+author, minDate.Time, maxDate, func(issue restIssue) error {
+		repo, err := issue.repository()
+		if err != nil {
+			return err
+		}
+
+		return fetchIssueComments(ctx, client, repo, issue.Number, author, minDate, func(post Post) {
+			posts = append(posts, post)
+			spin.Progress("fetched %d posts", len(posts))
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	spin.Done("✔️ fetched %d posts", len(posts))
+
+	// Sort posts by update time in descending order
+	slices.SortFunc(posts, func(a1, a2 Post) int {
+		return a2.UpdatedAt.Compare(a1.UpdatedAt.Time)
+	})
+
+	return posts, nil
+}
+
+// searchIssues searches the issues and PRs matching the query, updated between minDate and maxDate.
+//
+// The search API returns at most 1000 results, so the date range is split in halves
+// until each range has less results than that.
+func searchIssues(ctx context.Context, client *gh.RESTClient, query string, minDate, maxDate time.Time, yield func(restIssue) error) error {
+	q := url.Values{
+		"q":        []string{fmt.Sprintf("%s updated:%s..%s", query, minDate.Format(time.RFC3339), maxDate.Format(time.RFC3339))},
+		"per_page": []string{"100"},
+	}
+
+	uri := "search/issues?" + q.Encode()
+	for page, err := range gh.Search[restIssue](ctx, client, uri) {
+		if err != nil {
+			return err
+		}
+
+		if page.TotalCount > searchMaxResults && maxDate.Sub(minDate) > time.Second {
+			// too many results, the date range is split before going further
+			middle := minDate.Add(maxDate.Sub(minDate) / 2).Truncate(time.Second)
+			if err := searchIssues(ctx, client, query, minDate, middle, yield); err != nil {
+				return err
+			}
+			return searchIssues(ctx, client, query, middle.Add(time.Second), maxDate, yield)
+		}
+
+		for _, issue := range page.Items {
+			if err := yield(issue); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// fetchIssueComments fetches the comments of an issue made by the author since minDate.
+func fetchIssueComments(ctx context.Context, client *gh.RESTClient, repo gh.Repository, number int, author string, minDate timeago.RelativeDate, addPost func(Post)) error {
+	q := url.Values{
+		"per_page": []string{"100"},
+	}
+	if !minDate.IsZero() {
+		q.Set("since", minDate.Format(time.RFC3339))
+	}
+
+	uri := fmt.Sprintf("repos/%s/%s/issues/%d/comments?%s", repo.Owner, repo.Name, number, q.Encode())
+	for comments, err := range gh.Paginate[restComment](ctx, client, uri) {
+		if err != nil {
+			return err
+		}
+
+		for _, comment := range comments {
+			if !authoredBy(comment.Author, author) {
+				continue
+			}
+
+			addPost(Post{
+				Repository: repo,
+				Type:       PostTypeComment,
+				CreatedAt:  comment.CreatedAt,
+				UpdatedAt:  comment.UpdatedAt,
+				Content:    comment.Body,
+				Author:     comment.Author,
+				Link:       comment.Link,
+				ID:         strconv.Itoa(comment.ID),
+			})
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ccoVeille/gh-reaction/internal/gh"
+)
+
+func TestSearchIssues(t *testing.T) {
+	minDate := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	maxDate := minDate.AddDate(0, 0, 10)
+
+	// an issue updated every 6 hours, the stub pretends each of them stands for 300 results:
+	// the ranges with more than 3 issues are over the limit of the search API
+	const resultsPerIssue = 300
+	var issues []time.Time
+	for d := minDate; !d.After(maxDate); d = d.Add(6 * time.Hour) {
+		issues = append(issues, d)
+	}
+
+	var ranges [][2]time.Time
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/search/issues" {
+			http.NotFound(w, r)
+			return
+		}
+
+		_, dates, found := strings.Cut(r.URL.Query().Get("q"), " updated:")
+		if !found {
+			t.Errorf("missing the updated qualifier in %q", r.URL.Query().Get("q"))
+			http.Error(w, "bad query", http.StatusUnprocessableEntity)
+			return
+		}
+		from, to, _ := strings.Cut(dates, "..")
+		since, err := time.Parse(time.RFC3339, from)
+		if err != nil {
+			t.Error(err)
+		}
+		until, err := time.Parse(time.RFC3339, to)
+		if err != nil {
+			t.Error(err)
+		}
+		ranges = append(ranges, [2]time.Time{since, until})
+
+		result := gh.SearchResult[restIssue]{}
+		for i, updatedAt := range issues {
+			if updatedAt.Before(since) || updatedAt.After(until) {
+				continue
+			}
+			result.TotalCount += resultsPerIssue
+			issue := restIssue{Number: i + 1}
+			issue.UpdatedAt.Time = updatedAt
+			result.Items = append(result.Items, issue)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(result); err != nil {
+			t.Error(err)
+		}
+	}))
+
+	var numbers []int
+	err := searchIssues(t.Context(), client, "author:me", minDate, maxDate, func(issue restIssue) error {
+		numbers = append(numbers, issue.Number)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(numbers) != len(issues) {
+		t.Fatalf("expected %d issues, got %d: %v", len(issues), len(numbers), numbers)
+	}
+	for i, number := range numbers {
+		// the ranges are searched from the oldest one
+		if number != i+1 {
+			t.Errorf("expected issue %d at position %d, got issue %d", i+1, i, number)
+		}
+	}
+
+	if len(ranges) < 2 {
+		t.Fatalf("expected the date range to be split, got %d searches", len(ranges))
+	}
+	if !ranges[0][0].Equal(minDate) || !ranges[0][1].Equal(maxDate) {
+		t.Errorf("expected the first search to cover %s..%s, got %s..%s", minDate, maxDate, ranges[0][0], ranges[0][1])
+	}
+}
+
+func TestSearchIssuesError(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"message": "Validation Failed"}`, http.StatusUnprocessableEntity)
+	}))
+
+	var yielded int
+	err := searchIssues(t.Context(), client, "author:me", time.Now().AddDate(0, -1, 0), time.Now(), func(restIssue) error {
+		yielded++
+		return nil
+	})
+	if err == nil {
+		t.Error("expected an error")
+	}
+	if yielded != 0 {
+		t.Errorf("expected no issue, got %d", yielded)
+	}
+}
+
+func TestRestIssueRepository(t *testing.T) {
+	tests := []struct {
+		url      string
+		expected string
+	}{
+		{url: "https://api.github.com/repos/owner/repo", expected: "github.com/owner/repo"},
+		{url: "https://github.example.com/api/v3/repos/owner/repo", expected: "github.example.com/owner/repo"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			repo, err := restIssue{RepositoryURL: tt.url}.repository()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := repo.Host + "/" + repo.Owner + "/" + repo.Name
+			if got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
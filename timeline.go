@@ -0,0 +1,213 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ccoVeille/gh-reaction/internal/timeago"
+)
+
+// timelineUnit is the duration of the buckets of a [Timeline].
+type timelineUnit string
+
+const (
+	timelineDay   timelineUnit = "day"
+	timelineWeek  timelineUnit = "week"
+	timelineMonth timelineUnit = "month"
+)
+
+var timelineUnits = []timelineUnit{
+	timelineDay,
+	timelineWeek,
+	timelineMonth,
+}
+
+// String returns the name of the unit.
+//
+// It satisfies the [flag.Value] and [fmt.Stringer] interface.
+func (u timelineUnit) String() string {
+	return string(u)
+}
+
+// Set sets the unit from its name.
+//
+// It satisfies the [flag.Value] interface.
+func (u *timelineUnit) Set(value string) error {
+	for _, unit := range timelineUnits {
+		if strings.EqualFold(value, string(unit)) {
+			*u = unit
+			return nil
+		}
+	}
+	return fmt.Errorf("unsupported timeline unit %q, expected one of %v", value, timelineUnits)
+}
+
+// start returns the start of the bucket containing t, in the location of t.
+//
+// The weeks start on Monday.
+func (u timelineUnit) start(t time.Time) time.Time {
+	year, month, day := t.Date()
+	switch u {
+	case timelineMonth:
+		return time.Date(year, month, 1, 0, 0, 0, 0, t.Location())
+	case timelineWeek:
+		daysSinceMonday := (int(t.Weekday()) + 6) % 7
+		return time.Date(year, month, day-daysSinceMonday, 0, 0, 0, 0, t.Location())
+	default:
+		return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+	}
+}
+
+// next returns the start of the bucket following the one starting at t.
+func (u timelineUnit) next(t time.Time) time.Time {
+	switch u {
+	case timelineMonth:
+		return t.AddDate(0, 1, 0)
+	case timelineWeek:
+		return t.AddDate(0, 0, 7)
+	default:
+		return t.AddDate(0, 0, 1)
+	}
+}
+
+// label returns the name of the bucket starting at t.
+func (u timelineUnit) label(t time.Time) string {
+	if u == timelineMonth {
+		return t.Format("2006-01")
+	}
+	return t.Format(time.DateOnly)
+}
+
+// Timeline holds the number of reactions given per day, week or month.
+type Timeline struct {
+	Unit     timelineUnit     `json:"unit"`
+	TimeZone string           `json:"time_zone"`
+	Buckets  []TimelineBucket `json:"buckets"`
+}
+
+// TimelineBucket holds the reactions given during a day, a week or a month of a [Timeline].
+type TimelineBucket struct {
+	Start     time.Time           `json:"start"`
+	Total     int                 `json:"total"`
+	Reactions ValueCounts[string] `json:"reactions"`
+}
+
+// newTimeline buckets the reactions given in the period, in the time zone.
+//
+// There is a bucket for every day, week or month of the period, even the ones without reactions.
+func newTimeline(reactions Reactions, unit timelineUnit, loc *time.Location, period timeago.DateRange) Timeline {
+	end := time.Now()
+	if !period.Until.IsZero() {
+		end = period.Until.Time
+	}
+
+	timeline := Timeline{
+		Unit:     unit,
+		TimeZone: timeZoneName(loc),
+	}
+	for start := unit.start(period.Since.In(loc)); start.Before(end); start = unit.next(start) {
+		timeline.Buckets = append(timeline.Buckets, TimelineBucket{Start: start})
+	}
+
+	byBucket := make([]Reactions, len(timeline.Buckets))
+	for _, reaction := range reactions {
+		createdAt := reaction.Reaction.CreatedAt.Time
+		if !period.Contains(createdAt) {
+			continue
+		}
+
+		start := unit.start(createdAt.In(loc))
+		i, found := slices.BinarySearchFunc(timeline.Buckets, start, func(b TimelineBucket, t time.Time) int {
+			return b.Start.Compare(t)
+		})
+		if found {
+			byBucket[i] = append(byBucket[i], reaction)
+		}
+	}
+
+	for i, bucketReactions := range byBucket {
+		timeline.Buckets[i].Total = len(bucketReactions)
+		timeline.Buckets[i].Reactions = bucketReactions.Reactions().Sort()
+	}
+
+	return timeline
+}
+
+// timeZoneName returns the IANA name of the location, such as "Europe/Paris".
+//
+// The name of the Local location tells nothing, it's resolved from the TZ environment variable,
+// or from the target of /etc/localtime. It falls back to the offset from UTC, such as "UTC+02:00".
+func timeZoneName(loc *time.Location) string {
+	if loc != time.Local {
+		return loc.String()
+	}
+
+	if tz, found := os.LookupEnv("TZ"); found {
+		tz = strings.TrimPrefix(tz, ":")
+		if tz == "" {
+			// an empty TZ means UTC
+			return time.UTC.String()
+		}
+		if _, err := time.LoadLocation(tz); err == nil {
+			return tz
+		}
+	}
+
+	if target, err := os.Readlink("/etc/localtime"); err == nil {
+		if _, name, found := strings.Cut(target, "zoneinfo/"); found {
+			return name
+		}
+	}
+
+	_, offset := time.Now().In(loc).Zone()
+	sign := '+'
+	if offset < 0 {
+		sign, offset = '-', -offset
+	}
+	return fmt.Sprintf("UTC%c%02d:%02d", sign, offset/3600, offset%3600/60)
+}
+
+// Counts returns the number of reactions of each bucket.
+func (t Timeline) Counts() []int {
+	counts := make([]int, 0, len(t.Buckets))
+	for _, bucket := range t.Buckets {
+		counts = append(counts, bucket.Total)
+	}
+	return counts
+}
+
+// sparkline renders the values with block characters of increasing height, the zeros with the lowest one.
+func sparkline(values []int) string {
+	const ticks = "▁▂▃▄▅▆▇█"
+	levels := []rune(ticks)
+
+	maxValue := 0
+	for _, v := range values {
+		maxValue = max(maxValue, v)
+	}
+
+	var sb strings.Builder
+	for _, v := range values {
+		if v <= 0 {
+			sb.WriteRune(levels[0])
+			continue
+		}
+		// round up, so the smallest values are visible
+		sb.WriteRune(levels[(v*(len(levels)-1)+maxValue-1)/maxValue])
+	}
+	return sb.String()
+}
+
+// bar renders the value as a horizontal bar, width is the length of the bar of maxValue.
+func bar(value, maxValue, width int) string {
+	if value <= 0 || maxValue <= 0 {
+		return ""
+	}
+
+	partials := []rune(" ▏▎▍▌▋▊▉")
+	eighths := value * width * 8 / maxValue
+	return strings.Repeat("█", eighths/8) + strings.TrimSpace(string(partials[eighths%8]))
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/ccoVeille/gh-reaction/internal/timeago"
+)
+
+func TestTimelineUnitStart(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		unit     timelineUnit
+		t        time.Time
+		expected time.Time
+	}{
+		{"Day", timelineDay, time.Date(2024, 3, 5, 18, 30, 0, 0, time.UTC), time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)},
+		{"Week on Monday", timelineWeek, time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC), time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)},
+		{"Week on Sunday", timelineWeek, time.Date(2024, 3, 10, 23, 0, 0, 0, time.UTC), time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)},
+		{"Week across months", timelineWeek, time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC), time.Date(2024, 2, 26, 0, 0, 0, 0, time.UTC)},
+		{"Week across years", timelineWeek, time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC), time.Date(2024, 12, 30, 0, 0, 0, 0, time.UTC)},
+		{"Month", timelineMonth, time.Date(2024, 2, 29, 12, 0, 0, 0, time.UTC), time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{"DST day", timelineDay, time.Date(2024, 3, 31, 23, 30, 0, 0, paris), time.Date(2024, 3, 31, 0, 0, 0, 0, paris)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.unit.start(tt.t)
+			if !got.Equal(tt.expected) || got.Location() != tt.expected.Location() {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestNewTimeline(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the clocks move forward on March 31st, 2024 in Paris
+	period := timeago.DateRange{
+		Since: timeago.NewRelativeDate(time.Date(2024, 3, 30, 0, 0, 0, 0, paris)),
+		Until: timeago.NewRelativeDate(time.Date(2024, 4, 2, 0, 0, 0, 0, paris)),
+	}
+
+	var reactions Reactions
+	for _, reactedAt := range []time.Time{
+		time.Date(2024, 3, 29, 23, 59, 0, 0, paris), // before the period
+		time.Date(2024, 3, 30, 0, 0, 0, 0, paris),
+		time.Date(2024, 3, 31, 1, 0, 0, 0, paris),
+		time.Date(2024, 3, 31, 23, 30, 0, 0, paris), // 21:30 UTC
+		time.Date(2024, 3, 31, 23, 30, 0, 0, paris),
+		time.Date(2024, 4, 2, 0, 0, 0, 0, paris), // after the period
+	} {
+		var r ReactionTo
+		r.Reaction.Content = "+1"
+		r.Reaction.CreatedAt.Time = reactedAt.UTC()
+		reactions = append(reactions, r)
+	}
+
+	timeline := newTimeline(reactions, timelineDay, paris, period)
+	if timeline.TimeZone != "Europe/Paris" {
+		t.Errorf("expected the time zone Europe/Paris, got %q", timeline.TimeZone)
+	}
+
+	var labels []string
+	for _, bucket := range timeline.Buckets {
+		labels = append(labels, timelineDay.label(bucket.Start))
+	}
+	if expected := []string{"2024-03-30", "2024-03-31", "2024-04-01"}; !slices.Equal(labels, expected) {
+		t.Errorf("expected the buckets %v, got %v", expected, labels)
+	}
+
+	// the day without reactions is listed too
+	if expected := []int{1, 3, 0}; !slices.Equal(timeline.Counts(), expected) {
+		t.Errorf("expected the counts %v, got %v", expected, timeline.Counts())
+	}
+}
+
+func TestSparkline(t *testing.T) {
+	tests := []struct {
+		name     string
+		values   []int
+		expected string
+	}{
+		{"Empty", nil, ""},
+		{"Zeros", []int{0, 0}, "▁▁"},
+		{"Smallest value is visible", []int{0, 1, 100}, "▁▂█"},
+		{"Steps", []int{1, 2, 3, 4, 5, 6, 7}, "▂▃▄▅▆▇█"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sparkline(tt.values); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestBar(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    int
+		maxValue int
+		width    int
+		expected string
+	}{
+		{"Zero", 0, 10, 4, ""},
+		{"Zero maximum", 3, 0, 4, ""},
+		{"Maximum", 10, 10, 4, "████"},
+		{"Half", 5, 10, 4, "██"},
+		{"Partial", 1, 16, 4, "▎"},
+		{"Too small to be seen", 1, 100, 4, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := bar(tt.value, tt.maxValue, tt.width); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestTimeZoneName(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := timeZoneName(paris); got != "Europe/Paris" {
+		t.Errorf("expected Europe/Paris, got %q", got)
+	}
+
+	t.Setenv("TZ", "America/New_York")
+	if got := timeZoneName(time.Local); got != "America/New_York" {
+		t.Errorf("expected the Local time zone to be resolved from TZ, got %q", got)
+	}
+
+	t.Setenv("TZ", "")
+	if got := timeZoneName(time.Local); got != "UTC" {
+		t.Errorf("expected an empty TZ to be UTC, got %q", got)
+	}
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/ccoVeille/gh-reaction/internal/gh"
+	"github.com/ccoVeille/gh-reaction/internal/github"
+)
+
+// knownBots are the bots that are not reported as such by the GitHub API:
+// the classic OAuth apps post with a user account, of type "User".
+var knownBots = []string{
+	"codecov-commenter",
+}
+
+// usersFileName is the name of the file listing the users to ignore or to keep, in the configuration directory.
+const usersFileName = "users"
+
+// userFilter selects the users whose reactions are analyzed.
+//
+// The bots are ignored unless includeBots is set, see [isBot]. The users matching the ignore globs are ignored too,
+// while the ones matching the allow globs are always analyzed, even if they are bots.
+//
+// The reactions of the users to their own posts are ignored unless includeSelf is set.
+type userFilter struct {
+	includeBots bool
+	includeSelf bool
+	ignore      stringList
+	allow       stringList
+}
+
+// ReadConfig reads the users file of the configuration directory, when it exists.
+func (f *userFilter) ReadConfig() error {
+	dir, err := gh.ConfigDir()
+	if err != nil {
+		return err
+	}
+
+	err = f.ReadFile(filepath.Join(dir, usersFileName))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// ReadFile adds the users listed in a file, one login or glob per line.
+//
+// The users are ignored, unless the line starts with ! to always keep them.
+// The empty lines, and the lines starting with # are ignored.
+func (f *userFilter) ReadFile(name string) error {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return err
+	}
+
+	for line := range strings.Lines(string(data)) {
+		line = strings.ToLower(strings.TrimSpace(line))
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if login, found := strings.CutPrefix(line, "!"); found {
+			f.allow = append(f.allow, strings.TrimSpace(login))
+		} else {
+			f.ignore = append(f.ignore, line)
+		}
+	}
+	return nil
+}
+
+// match reports whether the reactions of the user are analyzed.
+func (f userFilter) match(u github.User) bool {
+	login := strings.ToLower(u.GetLogin())
+	if matchLogin(f.allow, login) {
+		return true
+	}
+	if matchLogin(f.ignore, login) {
+		return false
+	}
+	return f.includeBots || !isBot(u)
+}
+
+// validate checks the globs, so they can be matched without checking the errors.
+func (f userFilter) validate() error {
+	for _, pattern := range slices.Concat(f.ignore, f.allow) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid user glob %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// isBot reports whether the user is a bot, according to the GitHub API or to the list of known bots.
+func isBot(u github.User) bool {
+	return u.IsBot() || slices.Contains(knownBots, strings.ToLower(u.GetLogin()))
+}
+
+// isSelfReaction reports whether the reaction was given by the author of the post.
+func isSelfReaction(r ReactionTo) bool {
+	login := r.Reaction.User.GetLogin()
+	return login != "" && strings.EqualFold(login, r.Post.Author.GetLogin())
+}
+
+// matchLogin reports whether the login is one of the patterns, or matches one of them.
+//
+// The logins are compared as is first, as the brackets of the bots logins such as "renovate[bot]"
+// have a special meaning in a glob.
+func matchLogin(patterns []string, login string) bool {
+	return slices.Contains(patterns, login) || matchAny(patterns, login)
+}
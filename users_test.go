@@ -0,0 +1,83 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/ccoVeille/gh-reaction/internal/gh"
+	"github.com/ccoVeille/gh-reaction/internal/github"
+)
+
+func newUser(login, userType string) github.User {
+	var u github.User
+	u.Login = &login
+	u.Type = &userType
+	return u
+}
+
+func TestUserFilterMatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		filter   userFilter
+		user     github.User
+		expected bool
+	}{
+		{"User", userFilter{}, newUser("octocat", "User"), true},
+		{"Bot", userFilter{}, newUser("dependabot[bot]", "Bot"), false},
+		{"Known bot", userFilter{}, newUser("Codecov-Commenter", "User"), false},
+		{"Bots included", userFilter{includeBots: true}, newUser("codecov-commenter", "User"), true},
+		{"Ignored", userFilter{ignore: stringList{"octocat"}}, newUser("OctoCat", "User"), false},
+		{"Ignored glob", userFilter{ignore: stringList{"release-*"}}, newUser("release-bot", "User"), false},
+		{"Ignored bot login", userFilter{includeBots: true, ignore: stringList{"renovate[bot]"}}, newUser("renovate[bot]", "Bot"), false},
+		{"Allowed bot", userFilter{allow: stringList{"renovate[bot]"}}, newUser("renovate[bot]", "Bot"), true},
+		{"Allowed over ignored", userFilter{ignore: stringList{"release-*"}, allow: stringList{"release-manager"}}, newUser("release-manager", "User"), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.match(tt.user); got != tt.expected {
+				t.Errorf("expected %v for %s, got %v", tt.expected, tt.user, got)
+			}
+		})
+	}
+}
+
+func TestUserFilterReadConfig(t *testing.T) {
+	// the configuration directory is derived from these variables, depending on the OS
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", home)
+	t.Setenv("AppData", home)
+
+	var f userFilter
+	if err := f.ReadConfig(); err != nil {
+		t.Fatalf("expected a missing file to be ignored, got %v", err)
+	}
+
+	dir, err := gh.ConfigDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		t.Fatal(err)
+	}
+
+	content := "# bots\nRelease-Bot\n\n  cla-*  \n! Renovate[bot]\n"
+	if err := os.WriteFile(filepath.Join(dir, usersFileName), []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	f = userFilter{ignore: stringList{"octocat"}}
+	if err := f.ReadConfig(); err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := (stringList{"octocat", "release-bot", "cla-*"}); !slices.Equal(f.ignore, expected) {
+		t.Errorf("expected the ignored users %v, got %v", expected, f.ignore)
+	}
+	if expected := (stringList{"renovate[bot]"}); !slices.Equal(f.allow, expected) {
+		t.Errorf("expected the allowed users %v, got %v", expected, f.allow)
+	}
+}